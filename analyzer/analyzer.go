@@ -0,0 +1,188 @@
+// Package analyzer provides the analyzer that reports the mistakes of `taqc` tags at compile time.
+// The analyzer is runnable as a standalone command (`cmd/taqc-vet`) and from `go vet -vettool`.
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/moznion/taqc"
+	"github.com/moznion/taqc/gen"
+	"github.com/moznion/taqc/internal"
+	"golang.org/x/tools/go/analysis"
+)
+
+// Analyzer reports the problems of the structs that have `taqc` tags; e.g. empty parameter names, duplicated parameter names,
+// unsupported field types, unknown tag options, invalid option values, `timeLayout` that doesn't round-trip, and unexported tagged fields.
+var Analyzer = &analysis.Analyzer{
+	Name: "taqc",
+	Doc:  "report the mistakes of taqc struct tags",
+	Run:  run,
+}
+
+var (
+	encoderTypes string
+	decoder      bool
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&encoderTypes, "encoders", "", "comma-separated types that have the custom encoders (e.g. `Money,time.Month`); those aren't reported as unsupported")
+	Analyzer.Flags.BoolVar(&decoder, "decoder", false, "report the field types that cannot be decoded as well")
+}
+
+// sampleTime is the time to check the round-trip of the time layouts; each element differs from the others, unlike the reference time.
+var sampleTime = time.Date(2021, time.November, 23, 13, 14, 15, 123456789, time.UTC)
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	cfg := gen.Config{
+		Decoder: decoder,
+		// the library returns the errors of the encoders, so those are fine
+		Fallible: true,
+		Encoders: map[string]string{},
+	}
+	for _, typeName := range strings.Split(encoderTypes, ",") {
+		if typeName = strings.TrimSpace(typeName); typeName != "" {
+			// the function is never called; the analyzer only needs to know the type has the encoder
+			cfg.Encoders[typeName] = "encode"
+		}
+	}
+
+	// the problems of a nested struct are reported by both of that and the outer struct, so report each problem once.
+	// The problems of a nested struct of the other package are out of this package, so those are dropped.
+	reported := map[string]bool{}
+	report := func(pos token.Pos, message string) {
+		if !inFiles(pass.Files, pos) {
+			return
+		}
+		key := fmt.Sprintf("%d:%s", pos, message)
+		if reported[key] {
+			return
+		}
+		reported[key] = true
+		pass.Reportf(pos, "%s", message)
+	}
+
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				obj, ok := pass.TypesInfo.Defs[typeSpec.Name].(*types.TypeName)
+				if !ok || obj.IsAlias() {
+					// the problems of an alias are the ones of the original type, which are reported on that
+					continue
+				}
+				structType, ok := obj.Type().Underlying().(*types.Struct)
+				if !ok || !hasTaqcTag(structType) {
+					continue
+				}
+
+				diagnostics, err := gen.Diagnose(pass.Pkg, pass.Fset, obj.Name(), cfg)
+				if err != nil {
+					return nil, err
+				}
+				for _, diagnostic := range diagnostics {
+					if errors.Is(diagnostic, taqc.ErrUnknownTimeFormatter) {
+						// the time formatters are registered at runtime, so the analyzer cannot know them
+						continue
+					}
+					report(diagnostic.Pos, fmt.Sprintf("field %s: %s", diagnostic.FieldName, diagnostic.Message))
+				}
+				checkTimeLayouts(structType, report)
+				checkUnexportedFields(structType, report)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// hasTaqcTag returns whether any field of the struct has `taqc` tag.
+func hasTaqcTag(structType *types.Struct) bool {
+	for i := 0; i < structType.NumFields(); i++ {
+		if _, ok := reflect.StructTag(structType.Tag(i)).Lookup(internal.TagName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// inFiles returns whether given position is in any of the files.
+func inFiles(files []*ast.File, pos token.Pos) bool {
+	for _, file := range files {
+		if file.Pos() <= pos && pos < file.End() {
+			return true
+		}
+	}
+	return false
+}
+
+// checkUnexportedFields reports the unexported fields that have `taqc` tag, which the library rejects with taqc.ErrUnexportedField.
+// The anonymous structs in the struct are checked as well, like checkTimeLayouts.
+func checkUnexportedFields(structType *types.Struct, report func(pos token.Pos, message string)) {
+	for i := 0; i < structType.NumFields(); i++ {
+		field := structType.Field(i)
+		if nested, ok := field.Type().(*types.Struct); ok {
+			checkUnexportedFields(nested, report)
+			continue
+		}
+
+		if _, ok := reflect.StructTag(structType.Tag(i)).Lookup(internal.TagName); !ok || field.Exported() {
+			continue
+		}
+		report(field.Pos(), fmt.Sprintf("field %s: %s", field.Name(), taqc.ErrUnexportedField))
+	}
+}
+
+// checkTimeLayouts reports the fields whose `timeLayout` doesn't round-trip. The anonymous structs in the struct are checked as well;
+// the named ones are checked on their own.
+func checkTimeLayouts(structType *types.Struct, report func(pos token.Pos, message string)) {
+	for i := 0; i < structType.NumFields(); i++ {
+		field := structType.Field(i)
+		if nested, ok := field.Type().(*types.Struct); ok {
+			checkTimeLayouts(nested, report)
+			continue
+		}
+
+		tag, ok := reflect.StructTag(structType.Tag(i)).Lookup(internal.TagName)
+		if !ok {
+			continue
+		}
+		parsedTag, err := internal.ParseTag(tag)
+		if err != nil {
+			continue // reported by the diagnostics of the generator
+		}
+		timeLayout, ok := parsedTag.Option("timeLayout")
+		if !ok {
+			continue
+		}
+		err = checkTimeLayout(internal.ResolveTimeLayout(timeLayout))
+		if err != nil {
+			report(field.Pos(), fmt.Sprintf("field %s: timeLayout %q doesn't round-trip: %s", field.Name(), timeLayout, err))
+		}
+	}
+}
+
+// checkTimeLayout checks the time that is formatted by given layout can be parsed back to the same one.
+func checkTimeLayout(layout string) error {
+	formatted := sampleTime.Format(layout)
+	if formatted == layout {
+		return errors.New("that has no element of the reference time (Mon Jan 2 15:04:05 MST 2006)")
+	}
+	parsed, err := time.Parse(layout, formatted)
+	if err != nil {
+		return fmt.Errorf("the formatted time %q cannot be parsed: %w", formatted, err)
+	}
+	if reformatted := parsed.Format(layout); reformatted != formatted {
+		return fmt.Errorf("the formatted time %q is parsed as %q", formatted, reformatted)
+	}
+	return nil
+}
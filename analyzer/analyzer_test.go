@@ -0,0 +1,34 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a")
+}
+
+func TestAnalyzer_Encoders(t *testing.T) {
+	err := Analyzer.Flags.Set("encoders", "Money")
+	assert.NoError(t, err)
+	defer func() {
+		_ = Analyzer.Flags.Set("encoders", "")
+	}()
+
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "b")
+}
+
+func TestAnalyzer_NestedStructOfOtherPackage(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "c")
+}
+
+func TestCheckTimeLayout(t *testing.T) {
+	assert.NoError(t, checkTimeLayout("2006-01-02T15:04:05Z07:00"))
+	assert.NoError(t, checkTimeLayout("3:04PM"))
+	assert.NoError(t, checkTimeLayout("2006-01"))
+	assert.Error(t, checkTimeLayout("YYYY-MM-DD"))
+	assert.Error(t, checkTimeLayout("Mon 15:04"))
+}
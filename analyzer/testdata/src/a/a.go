@@ -0,0 +1,46 @@
+package a
+
+import "time"
+
+type Money struct {
+	Amount int
+}
+
+type Query struct {
+	Empty     string     `taqc:",omitempty"`                 // want `field Empty: query parameter name is empty in a tag`
+	Unknown   string     `taqc:"unknown, fooBar"`            // want `field Unknown: .*unknown tag option has come`
+	Since     time.Time  `taqc:"since, unixTimeUnit=minute"` // want `field Since: minute is unsupported: unsupported unix time unit has given`
+	Value     complex128 `taqc:"value"`                      // want `field Value: complex128 is unsupported`
+	Name      string     `taqc:"name"`
+	Duplicate string     `taqc:"name"`                          // want `field Duplicate: "name" is duplicated`
+	Layout    time.Time  `taqc:"layout, timeLayout=YYYY-MM-DD"` // want `field Layout: timeLayout "YYYY-MM-DD" doesn't round-trip: that has no element of the reference time`
+	Hour      time.Time  `taqc:"hour, timeLayout='Mon 15:04'"`  // want `field Hour: timeLayout "Mon 15:04" doesn't round-trip: the formatted time "Tue 13:14" is parsed as "Sat 13:14"`
+	Fine      time.Time  `taqc:"fine, timeLayout=RFC3339"`
+	Formatted time.Time  `taqc:"formatted, timeFormatter=unixFrac"`
+	Price     Money      `taqc:"price"` // want `field Price: Money is unsupported`
+}
+
+type Outer struct {
+	Inner Inner `taqc:"inner, inline, nestStyle=dot"`
+}
+
+type Inner struct {
+	Value complex64 `taqc:"value"` // want `field Value: complex64 is unsupported`
+}
+
+type NotTagged struct {
+	Value complex64
+}
+
+type Unexported struct {
+	Name  string `taqc:"name"`
+	value string `taqc:"value"` // want `field value: unexported field cannot have a tag`
+	other string
+}
+
+type Schedule struct {
+	Start time.Time `taqc:"start, timeLayout=2006-01-02, timeZone=Asia/Tokyo"`
+	Value complex64 `taqc:"value"` // want `field Value: complex64 is unsupported`
+}
+
+type ScheduleAlias = Schedule
@@ -0,0 +1,9 @@
+package b
+
+type Money struct {
+	Amount int
+}
+
+type Query struct {
+	Price Money `taqc:"price"`
+}
@@ -0,0 +1,9 @@
+package c
+
+import "d"
+
+type Outer struct {
+	Inner d.Inner `taqc:"inner, inline, nestStyle=dot"`
+}
+
+type InnerAlias = d.Inner
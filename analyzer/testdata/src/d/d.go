@@ -0,0 +1,5 @@
+package d
+
+type Inner struct {
+	Value complex64 `taqc:"value"`
+}
@@ -0,0 +1,12 @@
+// taqc-vet reports the mistakes of `taqc` tags. This runs standalone (e.g. `taqc-vet ./...`),
+// and from `go vet` as well (e.g. `go vet -vettool=$(which taqc-vet) ./...`).
+package main
+
+import (
+	"github.com/moznion/taqc/analyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,16 @@
+package tests
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=BoolFormatQueryParametersStructure --decoder"
+type BoolFormatQueryParametersStructure struct {
+	Default    bool              `taqc:"default"`
+	Numeric    bool              `taqc:"numeric, boolFormat=numeric"`
+	TrueFalse  bool              `taqc:"true_false, boolFormat=truefalse"`
+	YesNo      Flag              `taqc:"yes_no, boolFormat=yesno"`
+	OmitFalse  bool              `taqc:"omit_false, boolFormat=numeric, omitempty"`
+	Presence   bool              `taqc:"presence, boolFormat=presence"`
+	Ptr        *bool             `taqc:"ptr, boolFormat=yesno"`
+	Bools      []bool            `taqc:"bools"`
+	CSVFlags   []Flag            `taqc:"csv_flags, boolFormat=truefalse, collectionFormat=csv"`
+	Map        map[string]bool   `taqc:"map, boolFormat=numeric"`
+	MapOfBools map[string][]bool `taqc:"map_of_bools, boolFormat=yesno"`
+}
@@ -0,0 +1,16 @@
+package tests
+
+import "time"
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=CollectionQueryParametersStructure --decoder"
+type CollectionQueryParametersStructure struct {
+	Multi    []int64     `taqc:"multi, collectionFormat=multi"`
+	CSV      []string    `taqc:"csv, collectionFormat=csv"`
+	SSV      []float64   `taqc:"ssv, collectionFormat=ssv, floatFormat=shortest"`
+	Pipes    []string    `taqc:"pipes, collectionFormat=pipes"`
+	Brackets []uint8     `taqc:"brackets, collectionFormat=brackets"`
+	Indexed  []time.Time `taqc:"indexed, collectionFormat=indexed, unixTimeUnit=millisec"`
+	Empty    []string    `taqc:"empty, collectionFormat=csv"`
+	Kept     []string    `taqc:"kept, collectionFormat=brackets, keepEmpty"`
+	KeptCSV  []int64     `taqc:"kept_csv, collectionFormat=csv, keepEmpty"`
+}
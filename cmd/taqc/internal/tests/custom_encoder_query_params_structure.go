@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+type TenantID string
+
+var errInvalidMoney = errors.New("invalid money")
+
+func encodeMoney(m Money) ([]string, error) {
+	if m.Currency == "" {
+		return nil, errInvalidMoney
+	}
+	return []string{fmt.Sprintf("%d%s", m.Amount, m.Currency)}, nil
+}
+
+func encodeTenantID(id TenantID) ([]string, error) {
+	return []string{"tenant-" + string(id)}, nil
+}
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=CustomEncoderQueryParametersStructure --fallible --encoder=Money=encodeMoney --encoder=TenantID=encodeTenantID"
+type CustomEncoderQueryParametersStructure struct {
+	Price    Money      `taqc:"price"`
+	MaxPrice *Money     `taqc:"max_price"`
+	NilPrice *Money     `taqc:"nil_price"`
+	Prices   []Money    `taqc:"prices, collectionFormat=csv"`
+	Empty    []Money    `taqc:"empty, keepEmpty"`
+	Tenant   TenantID   `taqc:"tenant"`
+	Tenants  []TenantID `taqc:"tenants"`
+	Items    []Item     `taqc:"items, inline"`
+}
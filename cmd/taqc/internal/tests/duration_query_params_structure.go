@@ -0,0 +1,17 @@
+package tests
+
+import "time"
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=DurationQueryParametersStructure --decoder"
+type DurationQueryParametersStructure struct {
+	Default   time.Duration   `taqc:"default"`
+	Millisec  time.Duration   `taqc:"millisec, durationUnit=ms"`
+	Sec       time.Duration   `taqc:"sec, durationUnit=s"`
+	Min       time.Duration   `taqc:"min, durationUnit=m"`
+	Hour      time.Duration   `taqc:"hour, durationUnit=h"`
+	Go        time.Duration   `taqc:"go, durationFormat=go"`
+	Ptr       *time.Duration  `taqc:"ptr, durationUnit=s"`
+	NilPtr    *time.Duration  `taqc:"nil_ptr, durationUnit=s"`
+	Slice     []time.Duration `taqc:"slice, durationFormat=go, collectionFormat=csv"`
+	OmitEmpty time.Duration   `taqc:"omit_empty, durationUnit=s, omitempty"`
+}
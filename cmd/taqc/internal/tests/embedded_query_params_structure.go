@@ -0,0 +1,24 @@
+package tests
+
+type Pagination struct {
+	Limit  int64 `taqc:"limit"`
+	Offset int64 `taqc:"offset, omitempty"`
+}
+
+type Sorting struct {
+	Sort  string `taqc:"sort"`
+	Order string `taqc:"order"`
+}
+
+type cursor struct {
+	Cursor string `taqc:"cursor, omitempty"`
+}
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=EmbeddedQueryParametersStructure --decoder"
+type EmbeddedQueryParametersStructure struct {
+	Pagination
+	*Sorting
+	cursor
+	Order string `taqc:"order"` // shadows Sorting.Order
+	Query string `taqc:"q"`
+}
@@ -0,0 +1,20 @@
+package tests
+
+type LocalVarNames struct {
+	Bar   []string          `taqc:"bar"`
+	Nums  []int64           `taqc:"nums, collectionFormat=indexed"`
+	Attrs map[string]string `taqc:"attrs"`
+	Parts []Part            `taqc:"parts, inline, nestStyle=bracket"`
+}
+
+// LocalVarNamesQueryParametersStructure has the fields whose paths make the same local variable names in the generated code;
+// e.g. both of `FooBar` and `Foo.Bar` make `fooBarSlice`.
+//
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=LocalVarNamesQueryParametersStructure --decoder"
+type LocalVarNamesQueryParametersStructure struct {
+	FooBar   []string          `taqc:"fooBar"`
+	FooNums  []int64           `taqc:"fooNums, collectionFormat=indexed"`
+	FooAttrs map[string]string `taqc:"fooAttrs"`
+	FooParts []Part            `taqc:"fooParts, inline, nestStyle=bracket"`
+	Foo      LocalVarNames     `taqc:"foo, inline"`
+}
@@ -0,0 +1,33 @@
+package tests
+
+import "time"
+
+type FilterKey string
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=MapQueryParametersStructure --decoder"
+type MapQueryParametersStructure struct {
+	Filter map[string]string    `taqc:"filter"`
+	Tags   map[string][]string  `taqc:"tags, mapStyle=deepObject"`
+	Counts map[FilterKey]int64  `taqc:"counts, prefix=c_"`
+	Ratios map[string]float32   `taqc:"ratios, floatFormat=shortest"`
+	Flags  map[string]bool      `taqc:"flags"`
+	Times  map[string]time.Time `taqc:"times, unixTimeUnit=millisec"`
+	Page   Page                 `taqc:"page, inline, nestStyle=bracket"`
+	Query  string               `taqc:"q"`
+	Rest   map[string]string    `taqc:"rest, mapStyle=flat"`
+}
+
+type CatchAllItem struct {
+	SKU  string            `taqc:"sku"`
+	Rest map[string]string `taqc:"rest, mapStyle=flat"`
+	Tags []string          `taqc:"tags, collectionFormat=brackets"`
+}
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=CatchAllMapQueryParametersStructure --decoder"
+type CatchAllMapQueryParametersStructure struct {
+	IDs      []int64           `taqc:"ids, collectionFormat=brackets"`
+	Indexed  []string          `taqc:"idx, collectionFormat=indexed"`
+	Items    []CatchAllItem    `taqc:"items, inline"`
+	PtrItems []*CatchAllItem   `taqc:"ptrItems, inline, indexStyle=dot, nestStyle=bracket"`
+	Rest     map[string]string `taqc:"rest, mapStyle=flat"`
+}
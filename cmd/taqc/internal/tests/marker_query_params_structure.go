@@ -0,0 +1,14 @@
+package tests
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --decoder"
+
+//taqc:generate
+type MarkedQueryParametersStructure struct {
+	Keyword string `taqc:"q"`
+	Limit   int    `taqc:"limit, omitempty"`
+}
+
+//taqc:generate
+type AnotherMarkedQueryParametersStructure struct {
+	Cursor string `taqc:"cursor"`
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Color int
+
+func (c Color) String() string {
+	return [...]string{"red", "green", "blue"}[c]
+}
+
+type Status int
+
+const (
+	StatusActive Status = iota + 1
+	StatusClosed
+)
+
+var errInvalidStatus = errors.New("invalid status")
+
+func (s Status) MarshalText() ([]byte, error) {
+	switch s {
+	case StatusActive:
+		return []byte("active"), nil
+	case StatusClosed:
+		return []byte("closed"), nil
+	}
+	return nil, errInvalidStatus
+}
+
+func (s *Status) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "active":
+		*s = StatusActive
+	case "closed":
+		*s = StatusClosed
+	default:
+		return errInvalidStatus
+	}
+	return nil
+}
+
+// String must be ignored since MarshalText is preferred.
+func (s Status) String() string {
+	return "status"
+}
+
+type Ref struct {
+	Kind string
+	ID   int64
+}
+
+func (r *Ref) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%s:%d", r.Kind, r.ID)), nil
+}
+
+func (r *Ref) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(strings.Replace(string(text), ":", " ", 1), "%s %d", &r.Kind, &r.ID)
+	return err
+}
+
+// StringerQueryParametersStructure has no decoder, since `String()` has no counterpart to decode the value.
+//
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=StringerQueryParametersStructure"
+type StringerQueryParametersStructure struct {
+	Color  Color   `taqc:"color"`
+	Colors []Color `taqc:"colors, collectionFormat=csv"`
+}
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=MarshalerQueryParametersStructure --fallible --decoder"
+type MarshalerQueryParametersStructure struct {
+	Status     Status    `taqc:"status"`
+	StatusPtr  *Status   `taqc:"status_ptr"`
+	NilStatus  *Status   `taqc:"nil_status"`
+	Statuses   []Status  `taqc:"statuses"`
+	OmitStatus Status    `taqc:"omit_status, omitempty"`
+	Ref        Ref       `taqc:"ref"`
+	Refs       []Ref     `taqc:"refs"`
+	ZeroRef    Ref       `taqc:"zero_ref, omitzero"`
+	Time       time.Time `taqc:"time, omitzero"`
+}
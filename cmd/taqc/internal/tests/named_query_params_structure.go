@@ -0,0 +1,29 @@
+package tests
+
+import (
+	t2 "time"
+)
+
+type UserID int64
+
+type SortKey string
+
+type Ratio float32
+
+type Flag bool
+
+type UserIDs []UserID
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=NamedTypeQueryParametersStructure --decoder"
+type NamedTypeQueryParametersStructure struct {
+	UserID    UserID    `taqc:"userID, omitempty"`
+	SortKey   SortKey   `taqc:"sortKey"`
+	Ratio     Ratio     `taqc:"ratio, floatFormat=shortest"`
+	Flag      Flag      `taqc:"flag"`
+	Month     t2.Month  `taqc:"month"`
+	Since     t2.Time   `taqc:"since, unixTimeUnit=sec"`
+	UserIDPtr *UserID   `taqc:"userIDPtr"`
+	SortKeys  []SortKey `taqc:"sortKeys, keepEmpty"`
+	UserIDs   UserIDs   `taqc:"userIDs"`
+	Ratios    []Ratio   `taqc:"ratios"`
+}
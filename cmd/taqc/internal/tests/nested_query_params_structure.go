@@ -0,0 +1,28 @@
+package tests
+
+import "time"
+
+type Filter struct {
+	Status string    `taqc:"status"`
+	Owner  *string   `taqc:"owner"`
+	Labels []string  `taqc:"labels"`
+	Since  time.Time `taqc:"since, omitzero"`
+	Price  *Range    `taqc:"price, inline, nestStyle=bracket"`
+}
+
+type Range struct {
+	From int64 `taqc:"from"`
+	To   int64 `taqc:"to"`
+}
+
+type Page struct {
+	Size   int64 `taqc:"size"`
+	Number int64 `taqc:"number"`
+}
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=NestedQueryParametersStructure --decoder"
+type NestedQueryParametersStructure struct {
+	Filter *Filter `taqc:"filter, inline, prefix=filter."`
+	Page   Page    `taqc:"page, inline, nestStyle=bracket"`
+	Query  string  `taqc:"q"`
+}
@@ -2,7 +2,7 @@ package tests
 
 import "time"
 
-//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=PrimitiveQueryParamsStructure"
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=PrimitiveQueryParamsStructure --decoder"
 type PrimitiveQueryParamsStructure struct {
 	Foo             string  `taqc:"foo"`
 	Bar             int64   `taqc:"bar"`
@@ -11,7 +11,7 @@ type PrimitiveQueryParamsStructure struct {
 	ShouldBeIgnored string
 }
 
-//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=PointerQueryParamsStructure"
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=PointerQueryParamsStructure --decoder"
 type PointerQueryParamsStructure struct {
 	Foo             *string  `taqc:"foo"`
 	Bar             *int64   `taqc:"bar"`
@@ -20,7 +20,7 @@ type PointerQueryParamsStructure struct {
 	ShouldBeIgnored *string
 }
 
-//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=SliceQueryParamsStructure"
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=SliceQueryParamsStructure --decoder"
 type SliceQueryParamsStructure struct {
 	Foo             []string  `taqc:"foo"`
 	Bar             []int64   `taqc:"bar"`
@@ -28,14 +28,14 @@ type SliceQueryParamsStructure struct {
 	ShouldBeIgnored []string
 }
 
-//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=TimeQueryParametersStructure"
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=TimeQueryParametersStructure --decoder"
 type TimeQueryParametersStructure struct {
 	Time      time.Time   `taqc:"time"`
 	TimePtr   *time.Time  `taqc:"timePtr"`
 	TimeSlice []time.Time `taqc:"timeSlice"`
 }
 
-//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=PrimitiveTimeQueryParametersStructure"
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=PrimitiveTimeQueryParametersStructure --decoder"
 type PrimitiveTimeQueryParametersStructure struct {
 	UnixSec            time.Time `taqc:"sec"`
 	UnixSec2           time.Time `taqc:"sec2, unixTimeUnit=sec"`
@@ -45,9 +45,10 @@ type PrimitiveTimeQueryParametersStructure struct {
 	RFC3339            time.Time `taqc:"rfc3339, timeLayout=2006-01-02T15:04:05Z07:00"`
 	PrioritizedRFC3339 time.Time `taqc:"prioritized_rfc3339, unixTimeUnit=sec, timeLayout=2006-01-02T15:04:05Z07:00"`
 	//                                                     must be prioritized ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+	RFC1123 time.Time `taqc:"rfc1123, timeLayout='Mon, 02 Jan 2006 15:04:05 MST'"`
 }
 
-//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=PointerTimeQueryParametersStructure"
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=PointerTimeQueryParametersStructure --decoder"
 type PointerTimeQueryParametersStructure struct {
 	UnixSec            *time.Time `taqc:"sec"`
 	UnixSec2           *time.Time `taqc:"sec2, unixTimeUnit=sec"`
@@ -59,7 +60,7 @@ type PointerTimeQueryParametersStructure struct {
 	//                                                      must be prioritized ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 }
 
-//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=SliceTimeQueryParametersStructure"
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=SliceTimeQueryParametersStructure --decoder"
 type SliceTimeQueryParametersStructure struct {
 	UnixSec            []time.Time `taqc:"sec"`
 	UnixSec2           []time.Time `taqc:"sec2, unixTimeUnit=sec"`
@@ -70,3 +71,43 @@ type SliceTimeQueryParametersStructure struct {
 	PrioritizedRFC3339 []time.Time `taqc:"prioritized_rfc3339, unixTimeUnit=sec, timeLayout=2006-01-02T15:04:05Z07:00"`
 	//                                                       must be prioritized ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 }
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=OmitQueryParametersStructure --decoder"
+type OmitQueryParametersStructure struct {
+	Foo       string      `taqc:"foo, omitempty"`
+	Bar       int64       `taqc:"bar, omitempty"`
+	Buz       float64     `taqc:"buz, omitzero"`
+	Time      time.Time   `taqc:"time, omitzero"`
+	Slice     []string    `taqc:"slice, keepEmpty"`
+	TimeSlice []time.Time `taqc:"timeSlice, omitzero, keepEmpty"`
+}
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=FloatFormatQueryParametersStructure --decoder"
+type FloatFormatQueryParametersStructure struct {
+	Default   float64   `taqc:"default"`
+	Shortest  float64   `taqc:"shortest, floatFormat=shortest"`
+	Fixed     float64   `taqc:"fixed, floatFormat=fixed"`
+	Precision *float64  `taqc:"precision, floatPrecision=2"`
+	Exponent  []float64 `taqc:"exponent, floatFormat=exponent, floatPrecision=3"`
+}
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=NumericQueryParametersStructure --decoder"
+type NumericQueryParametersStructure struct {
+	Int        int       `taqc:"int"`
+	Int8       int8      `taqc:"int8"`
+	Int16      int16     `taqc:"int16"`
+	Int32      int32     `taqc:"int32"`
+	Uint       uint      `taqc:"uint, omitempty"`
+	Uint8      uint8     `taqc:"uint8"`
+	Uint16     uint16    `taqc:"uint16"`
+	Uint32     uint32    `taqc:"uint32"`
+	Uint64     uint64    `taqc:"uint64"`
+	Float32    float32   `taqc:"float32"`
+	Shortest32 float32   `taqc:"shortest32, floatFormat=shortest"`
+	IntPtr     *int      `taqc:"intPtr"`
+	Uint32Ptr  *uint32   `taqc:"uint32Ptr"`
+	Float32Ptr *float32  `taqc:"float32Ptr"`
+	Ints       []int     `taqc:"ints"`
+	Uint64s    []uint64  `taqc:"uint64s"`
+	Float32s   []float32 `taqc:"float32s, floatFormat=shortest"`
+}
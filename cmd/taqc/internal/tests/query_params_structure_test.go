@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/moznion/taqc"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -154,6 +155,7 @@ func TestPrimitiveTimeQueryParametersStructure_ToQueryParameters(t *testing.T) {
 		UnixNanoSec:        now,
 		RFC3339:            now,
 		PrioritizedRFC3339: now,
+		RFC1123:            now,
 	}
 
 	qp := q.ToQueryParameters()
@@ -165,6 +167,7 @@ func TestPrimitiveTimeQueryParametersStructure_ToQueryParameters(t *testing.T) {
 		"nanosec":             []string{fmt.Sprintf("%d", now.UnixNano())},
 		"rfc3339":             []string{now.Format(time.RFC3339)},
 		"prioritized_rfc3339": []string{now.Format(time.RFC3339)},
+		"rfc1123":             []string{now.Format(time.RFC1123)},
 	}, qp)
 }
 
@@ -225,3 +228,1247 @@ func TestSliceTimeQueryParametersStructure_ToQueryParameters(t *testing.T) {
 		"prioritized_rfc3339": []string{now.Format(time.RFC3339), now.Format(time.RFC3339)},
 	}, qp)
 }
+
+func TestPrimitiveQueryParamsStructure_FromQueryParameters(t *testing.T) {
+	q := &PrimitiveQueryParamsStructure{
+		ShouldBeIgnored: "should-be-kept",
+	}
+	err := q.FromQueryParameters(url.Values{
+		"foo": []string{"str"},
+		"bar": []string{"123"},
+		"buz": []string{"456.789000"},
+		"qux": []string{"1"},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, &PrimitiveQueryParamsStructure{
+		Foo:             "str",
+		Bar:             123,
+		Buz:             456.789,
+		Qux:             true,
+		ShouldBeIgnored: "should-be-kept",
+	}, q)
+
+	err = q.FromQueryParameters(url.Values{
+		"bar": []string{"not-a-number"},
+	})
+	assert.Error(t, err)
+
+	err = q.FromQueryParameters(url.Values{
+		"buz": []string{"not-a-number"},
+	})
+	assert.Error(t, err)
+}
+
+func TestPointerQueryParamsStructure_FromQueryParameters(t *testing.T) {
+	q := &PointerQueryParamsStructure{}
+	err := q.FromQueryParameters(url.Values{
+		"foo": []string{"str"},
+		"bar": []string{"123"},
+		"buz": []string{"456.789000"},
+		"qux": []string{"1"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "str", *q.Foo)
+	assert.Equal(t, int64(123), *q.Bar)
+	assert.Equal(t, 456.789, *q.Buz)
+	assert.True(t, *q.Qux)
+	assert.Nil(t, q.ShouldBeIgnored)
+
+	q = &PointerQueryParamsStructure{}
+	err = q.FromQueryParameters(url.Values{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, &PointerQueryParamsStructure{}, q)
+}
+
+func TestSliceQueryParamsStructure_FromQueryParameters(t *testing.T) {
+	q := &SliceQueryParamsStructure{}
+	err := q.FromQueryParameters(url.Values{
+		"foo": []string{"str", "value"},
+		"bar": []string{"123", "456"},
+		"buz": []string{"123.456000", "234.567000"},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, &SliceQueryParamsStructure{
+		Foo: []string{"str", "value"},
+		Bar: []int64{123, 456},
+		Buz: []float64{123.456, 234.567},
+	}, q)
+
+	err = q.FromQueryParameters(url.Values{
+		"bar": []string{"123", "not-a-number"},
+	})
+	assert.Error(t, err)
+}
+
+func TestPrimitiveTimeQueryParametersStructure_FromQueryParameters(t *testing.T) {
+	now := time.Now()
+	q := &PrimitiveTimeQueryParametersStructure{
+		UnixSec:            now,
+		UnixSec2:           now,
+		UnixMilliSec:       now,
+		UnixMicroSec:       now,
+		UnixNanoSec:        now,
+		RFC3339:            now,
+		PrioritizedRFC3339: now,
+		RFC1123:            now,
+	}
+
+	decoded := &PrimitiveTimeQueryParametersStructure{}
+	err := decoded.FromQueryParameters(q.ToQueryParameters())
+	assert.NoError(t, err)
+	assert.Equal(t, now.Unix(), decoded.UnixSec.Unix())
+	assert.Equal(t, now.Unix(), decoded.UnixSec2.Unix())
+	assert.Equal(t, now.UnixMilli(), decoded.UnixMilliSec.UnixMilli())
+	assert.Equal(t, now.UnixMicro(), decoded.UnixMicroSec.UnixMicro())
+	assert.Equal(t, now.UnixNano(), decoded.UnixNanoSec.UnixNano())
+	assert.Equal(t, now.Unix(), decoded.RFC3339.Unix())
+	assert.Equal(t, now.Unix(), decoded.PrioritizedRFC3339.Unix())
+	assert.Equal(t, now.Unix(), decoded.RFC1123.Unix())
+
+	err = decoded.FromQueryParameters(url.Values{
+		"rfc3339": []string{"not-a-date"},
+	})
+	assert.Error(t, err)
+}
+
+func TestPointerTimeQueryParametersStructure_FromQueryParameters(t *testing.T) {
+	now := time.Now()
+	q := &PointerTimeQueryParametersStructure{
+		UnixSec:     &now,
+		UnixNanoSec: &now,
+		RFC3339:     &now,
+	}
+
+	decoded := &PointerTimeQueryParametersStructure{}
+	err := decoded.FromQueryParameters(q.ToQueryParameters())
+	assert.NoError(t, err)
+	assert.Equal(t, now.Unix(), decoded.UnixSec.Unix())
+	assert.Equal(t, now.UnixNano(), decoded.UnixNanoSec.UnixNano())
+	assert.Equal(t, now.Unix(), decoded.RFC3339.Unix())
+	assert.Nil(t, decoded.UnixSec2)
+	assert.Nil(t, decoded.UnixMilliSec)
+	assert.Nil(t, decoded.UnixMicroSec)
+	assert.Nil(t, decoded.PrioritizedRFC3339)
+}
+
+func TestSliceTimeQueryParametersStructure_FromQueryParameters(t *testing.T) {
+	now := time.Now()
+	q := &SliceTimeQueryParametersStructure{
+		UnixMilliSec: []time.Time{now, now},
+		RFC3339:      []time.Time{now},
+	}
+
+	decoded := &SliceTimeQueryParametersStructure{}
+	err := decoded.FromQueryParameters(q.ToQueryParameters())
+	assert.NoError(t, err)
+	assert.Len(t, decoded.UnixMilliSec, 2)
+	assert.Equal(t, now.UnixMilli(), decoded.UnixMilliSec[1].UnixMilli())
+	assert.Len(t, decoded.RFC3339, 1)
+	assert.Equal(t, now.Unix(), decoded.RFC3339[0].Unix())
+	assert.Nil(t, decoded.UnixSec)
+}
+
+func TestOmitQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	q := &OmitQueryParametersStructure{}
+	qp := q.ToQueryParameters()
+	assert.EqualValues(t, url.Values{}, qp)
+
+	q = &OmitQueryParametersStructure{
+		Slice:     []string{},
+		TimeSlice: []time.Time{},
+	}
+	qp = q.ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"slice":     []string{""},
+		"timeSlice": []string{""},
+	}, qp)
+
+	now := time.Now()
+	q = &OmitQueryParametersStructure{
+		Foo:       "str",
+		Bar:       123,
+		Buz:       456.789,
+		Time:      now,
+		Slice:     []string{"str"},
+		TimeSlice: []time.Time{now},
+	}
+	qp = q.ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"foo":       []string{"str"},
+		"bar":       []string{"123"},
+		"buz":       []string{"456.789000"},
+		"time":      []string{fmt.Sprintf("%d", now.Unix())},
+		"slice":     []string{"str"},
+		"timeSlice": []string{fmt.Sprintf("%d", now.Unix())},
+	}, qp)
+}
+
+func TestOmitQueryParametersStructure_FromQueryParameters(t *testing.T) {
+	q := &OmitQueryParametersStructure{}
+	err := q.FromQueryParameters(url.Values{
+		"slice":     []string{""},
+		"timeSlice": []string{""},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, &OmitQueryParametersStructure{
+		Slice:     []string{},
+		TimeSlice: []time.Time{},
+	}, q)
+
+	q = &OmitQueryParametersStructure{}
+	err = q.FromQueryParameters(url.Values{
+		"slice": []string{"str", ""},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, &OmitQueryParametersStructure{
+		Slice: []string{"str", ""},
+	}, q)
+}
+
+func TestFloatFormatQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	precision := 123.456
+	q := &FloatFormatQueryParametersStructure{
+		Default:   123.456,
+		Shortest:  1e-9,
+		Fixed:     1e-9,
+		Precision: &precision,
+		Exponent:  []float64{123.456, 0.5},
+	}
+	qp := q.ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"default":   []string{"123.456000"},
+		"shortest":  []string{"1e-09"},
+		"fixed":     []string{"0.000000001"},
+		"precision": []string{"123.46"},
+		"exponent":  []string{"1.235e+02", "5.000e-01"},
+	}, qp)
+
+	decoded := &FloatFormatQueryParametersStructure{}
+	err := decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.Equal(t, 1e-9, decoded.Shortest)
+	assert.Equal(t, 1e-9, decoded.Fixed)
+	assert.Equal(t, 123.46, *decoded.Precision)
+	assert.Equal(t, []float64{123.5, 0.5}, decoded.Exponent)
+}
+
+func TestNumericQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	i := -123
+	u := uint32(456)
+	f := float32(7.89)
+	q := &NumericQueryParametersStructure{
+		Int:        -1,
+		Int8:       -128,
+		Int16:      -32768,
+		Int32:      -2147483648,
+		Uint:       0,
+		Uint8:      255,
+		Uint16:     65535,
+		Uint32:     4294967295,
+		Uint64:     18446744073709551615,
+		Float32:    1.5,
+		Shortest32: 0.1,
+		IntPtr:     &i,
+		Uint32Ptr:  &u,
+		Float32Ptr: &f,
+		Ints:       []int{1, -2},
+		Uint64s:    []uint64{3, 4},
+		Float32s:   []float32{0.1, 6.25},
+	}
+	qp := q.ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"int":        []string{"-1"},
+		"int8":       []string{"-128"},
+		"int16":      []string{"-32768"},
+		"int32":      []string{"-2147483648"},
+		"uint8":      []string{"255"},
+		"uint16":     []string{"65535"},
+		"uint32":     []string{"4294967295"},
+		"uint64":     []string{"18446744073709551615"},
+		"float32":    []string{"1.500000"},
+		"shortest32": []string{"0.1"},
+		"intPtr":     []string{"-123"},
+		"uint32Ptr":  []string{"456"},
+		"float32Ptr": []string{"7.890000"},
+		"ints":       []string{"1", "-2"},
+		"uint64s":    []string{"3", "4"},
+		"float32s":   []string{"0.1", "6.25"},
+	}, qp)
+
+	decoded := &NumericQueryParametersStructure{}
+	err := decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.EqualValues(t, q, decoded)
+
+	err = decoded.FromQueryParameters(url.Values{
+		"int8": []string{"128"},
+	})
+	assert.Error(t, err)
+}
+
+func TestNamedTypeQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	id := UserID(42)
+	since := time.Unix(1640000000, 0)
+	q := &NamedTypeQueryParametersStructure{
+		UserID:    123,
+		SortKey:   "name",
+		Ratio:     0.25,
+		Flag:      true,
+		Month:     time.March,
+		Since:     since,
+		UserIDPtr: &id,
+		SortKeys:  []SortKey{},
+		UserIDs:   UserIDs{1, 2},
+		Ratios:    []Ratio{1.5},
+	}
+	qp := q.ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"userID":    []string{"123"},
+		"sortKey":   []string{"name"},
+		"ratio":     []string{"0.25"},
+		"flag":      []string{"1"},
+		"month":     []string{"3"},
+		"since":     []string{"1640000000"},
+		"userIDPtr": []string{"42"},
+		"sortKeys":  []string{""},
+		"userIDs":   []string{"1", "2"},
+		"ratios":    []string{"1.500000"},
+	}, qp)
+
+	decoded := &NamedTypeQueryParametersStructure{}
+	err := decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.EqualValues(t, q, decoded)
+
+	qp = (&NamedTypeQueryParametersStructure{}).ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"sortKey": []string{""},
+		"ratio":   []string{"0"},
+		"month":   []string{"0"},
+		"since":   []string{fmt.Sprintf("%d", time.Time{}.Unix())},
+	}, qp)
+}
+
+func TestNestedQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	owner := "me"
+	q := &NestedQueryParametersStructure{
+		Filter: &Filter{
+			Status: "open",
+			Owner:  &owner,
+			Labels: []string{"bug", "help"},
+			Price: &Range{
+				From: 100,
+				To:   200,
+			},
+		},
+		Page: Page{
+			Size:   20,
+			Number: 2,
+		},
+		Query: "foo",
+	}
+	qp := q.ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"filter.status":      []string{"open"},
+		"filter.owner":       []string{"me"},
+		"filter.labels":      []string{"bug", "help"},
+		"filter.price[from]": []string{"100"},
+		"filter.price[to]":   []string{"200"},
+		"page[size]":         []string{"20"},
+		"page[number]":       []string{"2"},
+		"q":                  []string{"foo"},
+	}, qp)
+
+	decoded := &NestedQueryParametersStructure{}
+	err := decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.EqualValues(t, q, decoded)
+
+	qp = (&NestedQueryParametersStructure{}).ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"page[size]":   []string{"0"},
+		"page[number]": []string{"0"},
+		"q":            []string{""},
+	}, qp)
+
+	decoded = &NestedQueryParametersStructure{}
+	err = decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.Nil(t, decoded.Filter)
+}
+
+func TestNestedQueryParametersStructure_FromQueryParameters(t *testing.T) {
+	qp := url.Values{
+		"filter.status":    []string{"open"},
+		"filter.price[to]": []string{"200"},
+		"page[number]":     []string{"3"},
+		"q":                []string{"foo"},
+	}
+	decoded := &NestedQueryParametersStructure{}
+	err := decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.EqualValues(t, &NestedQueryParametersStructure{
+		Filter: &Filter{
+			Status: "open",
+			Price:  &Range{To: 200},
+		},
+		Page:  Page{Number: 3},
+		Query: "foo",
+	}, decoded)
+
+	unmarshaled := &NestedQueryParametersStructure{}
+	err = taqc.UnmarshalQueryParams(qp, unmarshaled)
+	assert.NoError(t, err)
+	assert.EqualValues(t, unmarshaled, decoded)
+
+	err = (&NestedQueryParametersStructure{}).FromQueryParameters(url.Values{"filter.price[from]": []string{"cheap"}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "filter.price[from]")
+}
+
+func TestNestedQueryParametersStructure_RoundTrip(t *testing.T) {
+	owner := "me"
+	q := &NestedQueryParametersStructure{
+		Filter: &Filter{
+			Status: "open",
+			Owner:  &owner,
+			Labels: []string{"bug", "help"},
+			Since:  time.Unix(1640000000, 0),
+			Price:  &Range{From: 100, To: 200},
+		},
+		Page:  Page{Size: 20, Number: 2},
+		Query: "foo",
+	}
+	qp := q.ToQueryParameters()
+	reflected, err := taqc.ConvertToQueryParams(q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, reflected, qp)
+
+	decoded := &NestedQueryParametersStructure{}
+	err = decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.Equal(t, q, decoded)
+
+	unmarshaled := &NestedQueryParametersStructure{}
+	err = taqc.UnmarshalQueryParams(qp, unmarshaled)
+	assert.NoError(t, err)
+	assert.Equal(t, q, unmarshaled)
+}
+
+func TestEmbeddedQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	q := &EmbeddedQueryParametersStructure{
+		Pagination: Pagination{
+			Limit:  10,
+			Offset: 20,
+		},
+		Sorting: &Sorting{
+			Sort: "name",
+		},
+		cursor: cursor{
+			Cursor: "abc",
+		},
+		Order: "desc",
+		Query: "foo",
+	}
+	qp := q.ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"limit":  []string{"10"},
+		"offset": []string{"20"},
+		"sort":   []string{"name"},
+		"cursor": []string{"abc"},
+		"order":  []string{"desc"},
+		"q":      []string{"foo"},
+	}, qp)
+
+	decoded := &EmbeddedQueryParametersStructure{}
+	err := decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.EqualValues(t, q, decoded)
+
+	qp = (&EmbeddedQueryParametersStructure{}).ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"limit": []string{"0"},
+		"order": []string{""},
+		"q":     []string{""},
+	}, qp)
+
+	decoded = &EmbeddedQueryParametersStructure{}
+	err = decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.Nil(t, decoded.Sorting)
+}
+
+func TestEmbeddedQueryParametersStructure_FromQueryParameters(t *testing.T) {
+	qp := url.Values{
+		"limit":  []string{"10"},
+		"sort":   []string{"name"},
+		"cursor": []string{"abc"},
+		"order":  []string{"desc"},
+	}
+	decoded := &EmbeddedQueryParametersStructure{}
+	err := decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	// "order" goes to the shadowing field, not to Sorting.Order
+	assert.EqualValues(t, &EmbeddedQueryParametersStructure{
+		Pagination: Pagination{Limit: 10},
+		Sorting:    &Sorting{Sort: "name"},
+		cursor:     cursor{Cursor: "abc"},
+		Order:      "desc",
+	}, decoded)
+
+	unmarshaled := &EmbeddedQueryParametersStructure{}
+	err = taqc.UnmarshalQueryParams(qp, unmarshaled)
+	assert.NoError(t, err)
+	assert.EqualValues(t, unmarshaled, decoded)
+
+	err = (&EmbeddedQueryParametersStructure{}).FromQueryParameters(url.Values{"offset": []string{"ten"}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "offset")
+}
+
+func TestEmbeddedQueryParametersStructure_RoundTrip(t *testing.T) {
+	q := &EmbeddedQueryParametersStructure{
+		Pagination: Pagination{Limit: 10, Offset: 20},
+		Sorting:    &Sorting{Sort: "name"},
+		cursor:     cursor{Cursor: "abc"},
+		Order:      "desc",
+		Query:      "foo",
+	}
+	qp := q.ToQueryParameters()
+	reflected, err := taqc.ConvertToQueryParams(q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, reflected, qp)
+
+	decoded := &EmbeddedQueryParametersStructure{}
+	err = decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.Equal(t, q, decoded)
+
+	unmarshaled := &EmbeddedQueryParametersStructure{}
+	err = taqc.UnmarshalQueryParams(qp, unmarshaled)
+	assert.NoError(t, err)
+	assert.Equal(t, q, unmarshaled)
+}
+
+func TestMapQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	q := &MapQueryParametersStructure{
+		Filter: map[string]string{
+			"status": "open",
+			"owner":  "me",
+		},
+		Tags: map[string][]string{
+			"label": {"bug", "help"},
+		},
+		Counts: map[FilterKey]int64{
+			"b": 2,
+			"a": 1,
+		},
+		Ratios: map[string]float32{
+			"x": 0.5,
+		},
+		Flags: map[string]bool{
+			"on":  true,
+			"off": false,
+		},
+		Times: map[string]time.Time{
+			"since": time.UnixMilli(1640000000123),
+		},
+		Query: "foo",
+		Rest: map[string]string{
+			"other": "bar",
+		},
+	}
+	qp := q.ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"filter[owner]":  []string{"me"},
+		"filter[status]": []string{"open"},
+		"tags[label]":    []string{"bug", "help"},
+		"c_a":            []string{"1"},
+		"c_b":            []string{"2"},
+		"ratios[x]":      []string{"0.5"},
+		"flags[on]":      []string{"1"},
+		"times[since]":   []string{"1640000000123"},
+		"page[size]":     []string{"0"},
+		"page[number]":   []string{"0"},
+		"q":              []string{"foo"},
+		"other":          []string{"bar"},
+	}, qp)
+
+	decoded := &MapQueryParametersStructure{}
+	err := decoded.FromQueryParameters(url.Values{
+		"filter[owner]":  []string{"me"},
+		"filter[status]": []string{"open"},
+		"tags[label]":    []string{"bug", "help"},
+		"c_a":            []string{"1"},
+		"times[since]":   []string{"1640000000123"},
+		"page[size]":     []string{"10"},
+		"q":              []string{"foo"},
+		"other":          []string{"bar"},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, map[string]string{"owner": "me", "status": "open"}, decoded.Filter)
+	assert.EqualValues(t, map[string][]string{"label": {"bug", "help"}}, decoded.Tags)
+	assert.EqualValues(t, map[FilterKey]int64{"a": 1}, decoded.Counts)
+	assert.True(t, time.UnixMilli(1640000000123).Equal(decoded.Times["since"]))
+	assert.Nil(t, decoded.Ratios)
+	assert.Equal(t, int64(10), decoded.Page.Size)
+	assert.Equal(t, "foo", decoded.Query)
+	assert.Equal(t, "bar", decoded.Rest["other"])
+	assert.NotContains(t, decoded.Rest, "q")
+	assert.NotContains(t, decoded.Rest, "page[size]")
+
+	err = decoded.FromQueryParameters(url.Values{"c_a": []string{"foo"}})
+	assert.Error(t, err)
+}
+
+func TestMapQueryParametersStructure_RoundTrip(t *testing.T) {
+	q := &MapQueryParametersStructure{
+		Filter: map[string]string{"a": "x"},
+		Tags:   map[string][]string{"t": {"bug", "help"}},
+		Counts: map[FilterKey]int64{"k": 1},
+		Ratios: map[string]float32{"r": 0.5},
+		Flags:  map[string]bool{"f": true},
+		Times:  map[string]time.Time{"x": time.UnixMilli(1640000000123)},
+		Page:   Page{Size: 10, Number: 2},
+		Query:  "foo",
+		Rest:   map[string]string{"zz": "last", "other": "bar"},
+	}
+	qp := q.ToQueryParameters()
+	reflected, err := taqc.ConvertToQueryParams(q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, reflected, qp)
+
+	// the map without affixes must not take the parameters of the other map fields
+	decoded := &MapQueryParametersStructure{}
+	err = decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.Equal(t, q, decoded)
+
+	unmarshaled := &MapQueryParametersStructure{}
+	err = taqc.UnmarshalQueryParams(qp, unmarshaled)
+	assert.NoError(t, err)
+	assert.Equal(t, q, unmarshaled)
+}
+
+func TestCatchAllMapQueryParametersStructure_FromQueryParameters(t *testing.T) {
+	qp := url.Values{
+		"ids[]":             []string{"1", "2"},
+		"idx[0]":            []string{"a"},
+		"idx[1]":            []string{"b"},
+		"items[0].sku":      []string{"A"},
+		"items[0].tags[]":   []string{"x"},
+		"items[0].other":    []string{"in item"},
+		"ptrItems.0[sku]":   []string{"B"},
+		"ptrItems.0[other]": []string{"in ptr item"},
+		"other":             []string{"top"},
+		"idx[x]":            []string{"not indexed"},
+	}
+	// the map without affixes takes only the parameters that no other field produces
+	expected := &CatchAllMapQueryParametersStructure{
+		IDs:      []int64{1, 2},
+		Indexed:  []string{"a", "b"},
+		Items:    []CatchAllItem{{SKU: "A", Tags: []string{"x"}, Rest: map[string]string{"other": "in item"}}},
+		PtrItems: []*CatchAllItem{{SKU: "B", Rest: map[string]string{"other": "in ptr item"}}},
+		Rest:     map[string]string{"other": "top", "idx[x]": "not indexed"},
+	}
+
+	decoded := &CatchAllMapQueryParametersStructure{}
+	err := decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, decoded)
+
+	unmarshaled := &CatchAllMapQueryParametersStructure{}
+	err = taqc.UnmarshalQueryParams(qp, unmarshaled)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, unmarshaled)
+}
+
+func TestCollectionQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	q := &CollectionQueryParametersStructure{
+		Multi:    []int64{1, 2},
+		CSV:      []string{"a,b", "50%", "c"},
+		SSV:      []float64{1.5, 2},
+		Pipes:    []string{"a|b", "c d"},
+		Brackets: []uint8{3, 4},
+		Indexed:  []time.Time{time.UnixMilli(1640000000123), time.UnixMilli(1640000000456)},
+		Empty:    []string{},
+		Kept:     []string{},
+		KeptCSV:  []int64{},
+	}
+	qp := q.ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"multi":      []string{"1", "2"},
+		"csv":        []string{"a%2Cb,50%25,c"},
+		"ssv":        []string{"1.5 2"},
+		"pipes":      []string{"a%7Cb|c d"},
+		"brackets[]": []string{"3", "4"},
+		"indexed[0]": []string{"1640000000123"},
+		"indexed[1]": []string{"1640000000456"},
+		"kept[]":     []string{""},
+		"kept_csv":   []string{""},
+	}, qp)
+
+	decoded := &CollectionQueryParametersStructure{}
+	err := decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.EqualValues(t, q.Multi, decoded.Multi)
+	assert.EqualValues(t, q.CSV, decoded.CSV)
+	assert.EqualValues(t, q.SSV, decoded.SSV)
+	assert.EqualValues(t, q.Pipes, decoded.Pipes)
+	assert.EqualValues(t, q.Brackets, decoded.Brackets)
+	assert.Len(t, decoded.Indexed, 2)
+	assert.True(t, q.Indexed[1].Equal(decoded.Indexed[1]))
+	assert.Nil(t, decoded.Empty)
+	assert.EqualValues(t, []string{}, decoded.Kept)
+	assert.EqualValues(t, []int64{}, decoded.KeptCSV)
+
+	decoded = &CollectionQueryParametersStructure{}
+	err = decoded.FromQueryParameters(url.Values{
+		"indexed[0]": []string{"1640000000123"},
+		"indexed[2]": []string{"1640000000456"},
+		"kept_csv":   []string{"1,2"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, decoded.Indexed, 1)
+	assert.EqualValues(t, []int64{1, 2}, decoded.KeptCSV)
+
+	err = decoded.FromQueryParameters(url.Values{"kept_csv": []string{"1,foo"}})
+	assert.Error(t, err)
+	err = decoded.FromQueryParameters(url.Values{"csv": []string{"a,%zz"}})
+	assert.Error(t, err)
+}
+
+func TestCollectionQueryParametersStructure_FromQueryParameters(t *testing.T) {
+	qp := url.Values{
+		"multi":      []string{"1", "2"},
+		"csv":        []string{"a%2Cb,50%25,c"},
+		"ssv":        []string{"1.5 2"},
+		"pipes":      []string{"a%7Cb|c d"},
+		"brackets[]": []string{"3", "4"},
+		"indexed[0]": []string{"1640000000123"},
+		"indexed[1]": []string{"1640000000456"},
+		"empty":      []string{""},
+		"kept[]":     []string{""},
+		"kept_csv":   []string{""},
+	}
+	decoded := &CollectionQueryParametersStructure{}
+	err := decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.EqualValues(t, &CollectionQueryParametersStructure{
+		Multi:    []int64{1, 2},
+		CSV:      []string{"a,b", "50%", "c"},
+		SSV:      []float64{1.5, 2},
+		Pipes:    []string{"a|b", "c d"},
+		Brackets: []uint8{3, 4},
+		Indexed:  []time.Time{time.UnixMilli(1640000000123), time.UnixMilli(1640000000456)},
+		Empty:    []string{""},
+		Kept:     []string{},
+		KeptCSV:  []int64{},
+	}, decoded)
+
+	unmarshaled := &CollectionQueryParametersStructure{}
+	err = taqc.UnmarshalQueryParams(qp, unmarshaled)
+	assert.NoError(t, err)
+	assert.EqualValues(t, unmarshaled, decoded)
+
+	err = (&CollectionQueryParametersStructure{}).FromQueryParameters(url.Values{"brackets[]": []string{"256"}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "brackets")
+}
+
+func TestCollectionQueryParametersStructure_RoundTrip(t *testing.T) {
+	q := &CollectionQueryParametersStructure{
+		Multi:    []int64{1, 2},
+		CSV:      []string{"a,b", "50%", "c"},
+		SSV:      []float64{1.5, 2},
+		Pipes:    []string{"a|b", "c d"},
+		Brackets: []uint8{3, 4},
+		Indexed:  []time.Time{time.UnixMilli(1640000000123), time.UnixMilli(1640000000456)},
+		Kept:     []string{},
+		KeptCSV:  []int64{},
+	}
+	qp := q.ToQueryParameters()
+	reflected, err := taqc.ConvertToQueryParams(q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, reflected, qp)
+
+	decoded := &CollectionQueryParametersStructure{}
+	err = decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.Equal(t, q, decoded)
+
+	unmarshaled := &CollectionQueryParametersStructure{}
+	err = taqc.UnmarshalQueryParams(qp, unmarshaled)
+	assert.NoError(t, err)
+	assert.Equal(t, q, unmarshaled)
+}
+
+func TestSliceOfStructQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	qty := int64(2)
+	q := &SliceOfStructQueryParametersStructure{
+		Items: []Item{
+			{SKU: "A", Qty: &qty, Tags: []string{"x", "y"}, Attrs: map[string]string{"color": "red"}},
+			{SKU: "B", Parts: []Part{{Name: "p0"}, {Name: "p1"}}},
+		},
+		PtrItems: []*Item{
+			{SKU: "C"},
+			nil,
+			{SKU: "D"},
+		},
+	}
+	qp := q.ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"items[0].sku":            []string{"A"},
+		"items[0].qty":            []string{"2"},
+		"items[0].tags":           []string{"x,y"},
+		"items[0].attrs[color]":   []string{"red"},
+		"items[1].sku":            []string{"B"},
+		"items[1].parts[0][name]": []string{"p0"},
+		"items[1].parts[1][name]": []string{"p1"},
+		"ptrItems.0.sku":          []string{"C"},
+		"ptrItems.1.sku":          []string{"D"},
+		"page[size]":              []string{"0"},
+		"page[number]":            []string{"0"},
+	}, qp)
+
+	decoded := &SliceOfStructQueryParametersStructure{}
+	err := decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.EqualValues(t, q.Items, decoded.Items)
+	// the nil element is omitted without a gap of the indices
+	assert.EqualValues(t, []*Item{{SKU: "C"}, {SKU: "D"}}, decoded.PtrItems)
+
+	decoded = &SliceOfStructQueryParametersStructure{}
+	err = decoded.FromQueryParameters(url.Values{"page[size]": []string{"10"}})
+	assert.NoError(t, err)
+	assert.Nil(t, decoded.Items)
+	assert.Nil(t, decoded.PtrItems)
+
+	err = decoded.FromQueryParameters(url.Values{"items[0].qty": []string{"foo"}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "items[0].qty")
+}
+
+func TestSliceOfStructQueryParametersStructure_FromQueryParameters(t *testing.T) {
+	qp := url.Values{
+		"items[0].sku":            []string{"A"},
+		"items[0].tags":           []string{"x,y"},
+		"items[1].attrs[color]":   []string{"red"},
+		"items[1].parts[0][name]": []string{"p0"},
+		"items[3].sku":            []string{"unreachable"},
+		"ptrItems.0.qty":          []string{"5"},
+		"page[number]":            []string{"2"},
+	}
+	decoded := &SliceOfStructQueryParametersStructure{}
+	err := decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	// the elements are decoded until the first index that has no parameter
+	qty := int64(5)
+	assert.EqualValues(t, &SliceOfStructQueryParametersStructure{
+		Items: []Item{
+			{SKU: "A", Tags: []string{"x", "y"}},
+			{Attrs: map[string]string{"color": "red"}, Parts: []Part{{Name: "p0"}}},
+		},
+		PtrItems: []*Item{{Qty: &qty}},
+		Page:     Page{Number: 2},
+	}, decoded)
+
+	unmarshaled := &SliceOfStructQueryParametersStructure{}
+	err = taqc.UnmarshalQueryParams(qp, unmarshaled)
+	assert.NoError(t, err)
+	assert.EqualValues(t, unmarshaled, decoded)
+
+	err = (&SliceOfStructQueryParametersStructure{}).FromQueryParameters(url.Values{"items[0].parts[0][name]": []string{"p0"}, "ptrItems.0.qty": []string{"foo"}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ptrItems.0.qty")
+}
+
+func TestSliceOfStructQueryParametersStructure_RoundTrip(t *testing.T) {
+	qty := int64(2)
+	q := &SliceOfStructQueryParametersStructure{
+		Items: []Item{
+			{SKU: "A", Qty: &qty, Tags: []string{"x", "y"}, Attrs: map[string]string{"color": "red"}},
+			{SKU: "B", Parts: []Part{{Name: "p0"}, {Name: "p1"}}},
+		},
+		PtrItems: []*Item{{SKU: "C"}, {SKU: "D"}},
+		Page:     Page{Size: 10, Number: 1},
+	}
+	qp := q.ToQueryParameters()
+	reflected, err := taqc.ConvertToQueryParams(q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, reflected, qp)
+
+	decoded := &SliceOfStructQueryParametersStructure{}
+	err = decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.Equal(t, q, decoded)
+
+	unmarshaled := &SliceOfStructQueryParametersStructure{}
+	err = taqc.UnmarshalQueryParams(qp, unmarshaled)
+	assert.NoError(t, err)
+	assert.Equal(t, q, unmarshaled)
+}
+
+func TestSliceOfStructQueryParametersStructure_RoundTripWithNilElement(t *testing.T) {
+	q := &SliceOfStructQueryParametersStructure{
+		PtrItems: []*Item{{SKU: "C"}, nil, {SKU: "D"}, nil, {SKU: "E"}},
+	}
+	qp := q.ToQueryParameters()
+	reflected, err := taqc.ConvertToQueryParams(q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, reflected, qp)
+
+	// the elements after the nil ones are decoded as well
+	expected := &SliceOfStructQueryParametersStructure{
+		PtrItems: []*Item{{SKU: "C"}, {SKU: "D"}, {SKU: "E"}},
+	}
+	decoded := &SliceOfStructQueryParametersStructure{}
+	err = decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, decoded)
+
+	unmarshaled := &SliceOfStructQueryParametersStructure{}
+	err = taqc.UnmarshalQueryParams(qp, unmarshaled)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, unmarshaled)
+}
+
+func TestLocalVarNamesQueryParametersStructure_RoundTrip(t *testing.T) {
+	q := &LocalVarNamesQueryParametersStructure{
+		FooBar:   []string{"a", "b"},
+		FooNums:  []int64{1, 2},
+		FooAttrs: map[string]string{"x": "1"},
+		FooParts: []Part{{Name: "p0"}},
+		Foo: LocalVarNames{
+			Bar:   []string{"c"},
+			Nums:  []int64{3},
+			Attrs: map[string]string{"y": "2"},
+			Parts: []Part{{Name: "p1"}, {Name: "p2"}},
+		},
+	}
+	qp := q.ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"fooBar":            []string{"a", "b"},
+		"fooNums[0]":        []string{"1"},
+		"fooNums[1]":        []string{"2"},
+		"fooAttrs[x]":       []string{"1"},
+		"fooParts[0][name]": []string{"p0"},
+		"bar":               []string{"c"},
+		"nums[0]":           []string{"3"},
+		"attrs[y]":          []string{"2"},
+		"parts[0][name]":    []string{"p1"},
+		"parts[1][name]":    []string{"p2"},
+	}, qp)
+
+	decoded := &LocalVarNamesQueryParametersStructure{}
+	err := decoded.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	assert.Equal(t, q, decoded)
+}
+
+func TestCustomEncoderQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	q := &CustomEncoderQueryParametersStructure{
+		Price:    Money{Amount: 100, Currency: "JPY"},
+		MaxPrice: &Money{Amount: 200, Currency: "JPY"},
+		Prices:   []Money{{Amount: 1, Currency: "USD"}, {Amount: 2, Currency: "EUR"}},
+		Empty:    []Money{},
+		Tenant:   "foo",
+		Tenants:  []TenantID{"a", "b"},
+		Items:    []Item{{SKU: "A"}},
+	}
+	qp, err := q.ToQueryParameters()
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"price":        []string{"100JPY"},
+		"max_price":    []string{"200JPY"},
+		"prices":       []string{"1USD,2EUR"},
+		"empty":        []string{""},
+		"tenant":       []string{"tenant-foo"},
+		"tenants":      []string{"tenant-a", "tenant-b"},
+		"items[0].sku": []string{"A"},
+	}, qp)
+
+	_, err = (&CustomEncoderQueryParametersStructure{}).ToQueryParameters()
+	assert.ErrorIs(t, err, errInvalidMoney)
+	assert.Contains(t, err.Error(), "price")
+
+	_, err = (&CustomEncoderQueryParametersStructure{Price: q.Price, Prices: []Money{{}}}).ToQueryParameters()
+	assert.ErrorIs(t, err, errInvalidMoney)
+	assert.Contains(t, err.Error(), "prices")
+}
+
+func TestStringerQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	q := &StringerQueryParametersStructure{
+		Color:  2,
+		Colors: []Color{0, 1},
+	}
+	assert.EqualValues(t, url.Values{
+		"color":  []string{"blue"},
+		"colors": []string{"red,green"},
+	}, q.ToQueryParameters())
+}
+
+func TestMarshalerQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	statusClosed := StatusClosed
+	q := &MarshalerQueryParametersStructure{
+		Status:    StatusActive,
+		StatusPtr: &statusClosed,
+		Statuses:  []Status{StatusActive, StatusClosed},
+		Ref:       Ref{Kind: "user", ID: 1},
+		Refs:      []Ref{{Kind: "org", ID: 2}},
+	}
+	qp, err := q.ToQueryParameters()
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"status":     []string{"active"},
+		"status_ptr": []string{"closed"},
+		"statuses":   []string{"active", "closed"},
+		"ref":        []string{"user:1"},
+		"refs":       []string{"org:2"},
+	}, qp)
+
+	_, err = (&MarshalerQueryParametersStructure{Status: 42}).ToQueryParameters()
+	assert.ErrorIs(t, err, errInvalidStatus)
+	assert.Contains(t, err.Error(), "status")
+
+	_, err = (&MarshalerQueryParametersStructure{Status: StatusActive, Statuses: []Status{42}}).ToQueryParameters()
+	assert.ErrorIs(t, err, errInvalidStatus)
+	assert.Contains(t, err.Error(), "statuses")
+}
+
+func TestMarshalerQueryParametersStructure_FromQueryParameters(t *testing.T) {
+	var q MarshalerQueryParametersStructure
+	err := q.FromQueryParameters(url.Values{
+		"status":     []string{"active"},
+		"status_ptr": []string{"closed"},
+		"statuses":   []string{"closed", "active"},
+		"ref":        []string{"user:1"},
+		"refs":       []string{"org:2"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusActive, q.Status)
+	assert.Equal(t, StatusClosed, *q.StatusPtr)
+	assert.Nil(t, q.NilStatus)
+	assert.Equal(t, []Status{StatusClosed, StatusActive}, q.Statuses)
+	assert.Equal(t, Ref{Kind: "user", ID: 1}, q.Ref)
+	assert.Equal(t, []Ref{{Kind: "org", ID: 2}}, q.Refs)
+
+	err = q.FromQueryParameters(url.Values{"status": []string{"unknown"}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "status")
+}
+
+func TestDurationQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	d := 90*time.Minute + 30*time.Second + 500*time.Millisecond
+	q := &DurationQueryParametersStructure{
+		Default:  d,
+		Millisec: d,
+		Sec:      d,
+		Min:      d,
+		Hour:     d,
+		Go:       d,
+		Ptr:      &d,
+		Slice:    []time.Duration{time.Second, 90 * time.Second},
+	}
+	qp := q.ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"default":  []string{"5430500000000"},
+		"millisec": []string{"5430500"},
+		"sec":      []string{"5430"},
+		"min":      []string{"90"},
+		"hour":     []string{"1"},
+		"go":       []string{"1h30m30.5s"},
+		"ptr":      []string{"5430"},
+		"slice":    []string{"1s,1m30s"},
+	}, qp)
+}
+
+func TestDurationQueryParametersStructure_FromQueryParameters(t *testing.T) {
+	var q DurationQueryParametersStructure
+	err := q.FromQueryParameters(url.Values{
+		"default":  []string{"1500"},
+		"millisec": []string{"1500"},
+		"hour":     []string{"2"},
+		"go":       []string{"1h30m30.5s"},
+		"ptr":      []string{"90"},
+		"slice":    []string{"1s,1m30s"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1500*time.Nanosecond, q.Default)
+	assert.Equal(t, 1500*time.Millisecond, q.Millisec)
+	assert.Equal(t, 2*time.Hour, q.Hour)
+	assert.Equal(t, 90*time.Minute+30500*time.Millisecond, q.Go)
+	assert.Equal(t, 90*time.Second, *q.Ptr)
+	assert.Nil(t, q.NilPtr)
+	assert.Equal(t, []time.Duration{time.Second, 90 * time.Second}, q.Slice)
+
+	err = q.FromQueryParameters(url.Values{"go": []string{"90"}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "go")
+}
+
+func TestTimeZoneQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	ts := time.Date(2021, 12, 31, 20, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+	q := &TimeZoneQueryParametersStructure{
+		UTC:       ts,
+		Tokyo:     ts,
+		TokyoPtr:  &ts,
+		TokyoList: []time.Time{ts},
+		AsIs:      ts,
+		UnixInUTC: ts,
+	}
+	qp, err := q.ToQueryParameters()
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"utc":         []string{"2022-01-01T01:30:00Z"},
+		"tokyo":       []string{"2022-01-01 10:30"},
+		"tokyo_ptr":   []string{"2022-01-01T10:30:00+09:00"},
+		"tokyo_list":  []string{"2022-01-01 10:30"},
+		"as_is":       []string{"2021-12-31T20:30:00-05:00"},
+		"unix_in_utc": []string{fmt.Sprintf("%d", ts.Unix())},
+	}, qp)
+}
+
+func TestTimeZoneQueryParametersStructure_FromQueryParameters(t *testing.T) {
+	var q TimeZoneQueryParametersStructure
+	err := q.FromQueryParameters(url.Values{
+		"tokyo":       []string{"2022-01-01 10:30"},
+		"tokyo_ptr":   []string{"2021-12-31T20:30:00-05:00"},
+		"tokyo_list":  []string{"2022-01-01 10:30"},
+		"unix_in_utc": []string{"1641000600"},
+	})
+	assert.NoError(t, err)
+
+	expected := time.Date(2022, 1, 1, 1, 30, 0, 0, time.UTC)
+	assert.True(t, expected.Equal(q.Tokyo))
+	assert.Equal(t, "Asia/Tokyo", q.Tokyo.Location().String())
+	assert.True(t, expected.Equal(*q.TokyoPtr))
+	assert.Equal(t, "Asia/Tokyo", q.TokyoPtr.Location().String())
+	assert.Len(t, q.TokyoList, 1)
+	assert.True(t, expected.Equal(q.TokyoList[0]))
+	assert.True(t, expected.Equal(q.UnixInUTC))
+	assert.Equal(t, time.UTC, q.UnixInUTC.Location())
+}
+
+func TestTimeFormatterQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	ts := time.Date(2023, 11, 14, 22, 13, 20, 123000000, time.UTC)
+	q := &TimeFormatterQueryParametersStructure{
+		RFC3339:   ts,
+		DateOnly:  &ts,
+		Kitchen:   []time.Time{ts},
+		Frac:      ts,
+		FracPtr:   &ts,
+		FracList:  []time.Time{ts, ts.Add(time.Second)},
+		FracTokyo: ts,
+	}
+	qp, err := q.ToQueryParameters()
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"rfc3339":    []string{"2023-11-14T22:13:20Z"},
+		"date_only":  []string{"2023-11-14"},
+		"kitchen":    []string{"10:13PM"},
+		"frac":       []string{"1700000000.123"},
+		"frac_ptr":   []string{"1700000000.123"},
+		"frac_list":  []string{"1700000000.123", "1700000001.123"},
+		"frac_tokyo": []string{"1700000000.123"},
+	}, qp)
+}
+
+func TestTimeFormatterQueryParametersStructure_FromQueryParameters(t *testing.T) {
+	var q TimeFormatterQueryParametersStructure
+	err := q.FromQueryParameters(url.Values{
+		"rfc3339":    []string{"2023-11-14T22:13:20Z"},
+		"date_only":  []string{"2023-11-14"},
+		"frac":       []string{"1700000000.123"},
+		"frac_ptr":   []string{"1700000000.123"},
+		"frac_list":  []string{"1700000000.123", "1700000001.123"},
+		"frac_tokyo": []string{"1700000000.123"},
+	})
+	assert.NoError(t, err)
+
+	expected := time.Date(2023, 11, 14, 22, 13, 20, 123000000, time.UTC)
+	assert.True(t, expected.Truncate(time.Second).Equal(q.RFC3339))
+	assert.Equal(t, time.Date(2023, 11, 14, 0, 0, 0, 0, time.UTC), *q.DateOnly)
+	assert.True(t, expected.Equal(q.Frac))
+	assert.True(t, expected.Equal(*q.FracPtr))
+	assert.Len(t, q.FracList, 2)
+	assert.True(t, expected.Add(time.Second).Equal(q.FracList[1]))
+	assert.True(t, expected.Equal(q.FracTokyo))
+	assert.Equal(t, "Asia/Tokyo", q.FracTokyo.Location().String())
+
+	err = q.FromQueryParameters(url.Values{"frac": []string{"now"}})
+	assert.Error(t, err)
+}
+
+func TestBoolFormatQueryParametersStructure_ToQueryParameters(t *testing.T) {
+	f := false
+	q := &BoolFormatQueryParametersStructure{
+		Default:    true,
+		Presence:   true,
+		YesNo:      true,
+		Ptr:        &f,
+		Bools:      []bool{true, false},
+		CSVFlags:   []Flag{false, true},
+		Map:        map[string]bool{"a": true, "b": false},
+		MapOfBools: map[string][]bool{"c": {true, false}},
+	}
+	qp := q.ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"default":         []string{"1"},
+		"numeric":         []string{"0"},
+		"true_false":      []string{"false"},
+		"yes_no":          []string{"yes"},
+		"presence":        []string{""},
+		"ptr":             []string{"no"},
+		"bools":           []string{"1", "0"},
+		"csv_flags":       []string{"false,true"},
+		"map[a]":          []string{"1"},
+		"map[b]":          []string{"0"},
+		"map_of_bools[c]": []string{"yes", "no"},
+	}, qp)
+
+	qp = (&BoolFormatQueryParametersStructure{}).ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"numeric":    []string{"0"},
+		"true_false": []string{"false"},
+		"yes_no":     []string{"no"},
+	}, qp)
+}
+
+func TestBoolFormatQueryParametersStructure_FromQueryParameters(t *testing.T) {
+	qp, err := url.ParseQuery("default=1&numeric=0&true_false=true&yes_no=no&presence&ptr=yes&bools=1&bools=0&csv_flags=false,true&map[a]=1&map_of_bools[c]=yes&map_of_bools[c]=no")
+	assert.NoError(t, err)
+
+	q := BoolFormatQueryParametersStructure{Numeric: true, YesNo: true}
+	err = q.FromQueryParameters(qp)
+	assert.NoError(t, err)
+	tr := true
+	assert.Equal(t, BoolFormatQueryParametersStructure{
+		Default:    true,
+		Numeric:    false,
+		TrueFalse:  true,
+		YesNo:      false,
+		Presence:   true,
+		Ptr:        &tr,
+		Bools:      []bool{true, false},
+		CSVFlags:   []Flag{false, true},
+		Map:        map[string]bool{"a": true},
+		MapOfBools: map[string][]bool{"c": {true, false}},
+	}, q)
+
+	err = q.FromQueryParameters(url.Values{"yes_no": []string{"1"}})
+	assert.Error(t, err)
+}
+
+func TestMarkedQueryParametersStructures(t *testing.T) {
+	marked := MarkedQueryParametersStructure{Keyword: "taxi"}
+	qp := marked.ToQueryParameters()
+	assert.EqualValues(t, url.Values{
+		"q": []string{"taxi"},
+	}, qp)
+
+	another := AnotherMarkedQueryParametersStructure{}
+	err := another.FromQueryParameters(url.Values{"cursor": []string{"next"}})
+	assert.NoError(t, err)
+	assert.Equal(t, AnotherMarkedQueryParametersStructure{Cursor: "next"}, another)
+}
@@ -0,0 +1,20 @@
+package tests
+
+type Part struct {
+	Name string `taqc:"name"`
+}
+
+type Item struct {
+	SKU   string            `taqc:"sku"`
+	Qty   *int64            `taqc:"qty"`
+	Tags  []string          `taqc:"tags, collectionFormat=csv"`
+	Attrs map[string]string `taqc:"attrs"`
+	Parts []Part            `taqc:"parts, inline, nestStyle=bracket"`
+}
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=SliceOfStructQueryParametersStructure --decoder"
+type SliceOfStructQueryParametersStructure struct {
+	Items    []Item  `taqc:"items, inline"`
+	PtrItems []*Item `taqc:"ptrItems, inline, indexStyle=dot"`
+	Page     Page    `taqc:"page, inline, nestStyle=bracket"`
+}
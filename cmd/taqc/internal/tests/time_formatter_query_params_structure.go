@@ -0,0 +1,31 @@
+package tests
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+func formatUnixFrac(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/float64(time.Second), 'f', 3, 64)
+}
+
+func parseUnixFrac(s string) (time.Time, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(math.Round(frac*1000))*int64(time.Millisecond)), nil
+}
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=TimeFormatterQueryParametersStructure --time-formatter=unixFrac=formatUnixFrac --time-parser=unixFrac=parseUnixFrac --fallible --decoder"
+type TimeFormatterQueryParametersStructure struct {
+	RFC3339   time.Time   `taqc:"rfc3339, timeLayout=RFC3339"`
+	DateOnly  *time.Time  `taqc:"date_only, timeLayout=DateOnly"`
+	Kitchen   []time.Time `taqc:"kitchen, timeLayout=Kitchen"`
+	Frac      time.Time   `taqc:"frac, timeFormatter=unixFrac"`
+	FracPtr   *time.Time  `taqc:"frac_ptr, timeFormatter=unixFrac"`
+	FracList  []time.Time `taqc:"frac_list, timeFormatter=unixFrac"`
+	FracTokyo time.Time   `taqc:"frac_tokyo, timeFormatter=unixFrac, timeZone=Asia/Tokyo"`
+}
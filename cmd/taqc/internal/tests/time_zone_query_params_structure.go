@@ -0,0 +1,13 @@
+package tests
+
+import "time"
+
+//go:generate sh -c "go run $(cd ./\"$(git rev-parse --show-cdup)\" || exit; pwd)/cmd/taqc/taqc.go --type=TimeZoneQueryParametersStructure --fallible --decoder"
+type TimeZoneQueryParametersStructure struct {
+	UTC       time.Time   `taqc:"utc, timeLayout=2006-01-02T15:04:05Z07:00, timeZone=UTC"`
+	Tokyo     time.Time   `taqc:"tokyo, timeLayout=2006-01-02 15:04, timeZone=Asia/Tokyo"`
+	TokyoPtr  *time.Time  `taqc:"tokyo_ptr, timeLayout=2006-01-02T15:04:05Z07:00, timeZone=Asia/Tokyo"`
+	TokyoList []time.Time `taqc:"tokyo_list, timeLayout=2006-01-02 15:04, timeZone=Asia/Tokyo"`
+	AsIs      time.Time   `taqc:"as_is, timeLayout=2006-01-02T15:04:05Z07:00"`
+	UnixInUTC time.Time   `taqc:"unix_in_utc, timeZone=UTC"`
+}
@@ -2,17 +2,15 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
-	"path/filepath"
 	"strings"
 
-	"github.com/iancoleman/strcase"
-	g "github.com/moznion/gowrtr/generator"
-	"github.com/moznion/taqc/cmd/taqc/internal"
+	"github.com/moznion/taqc/gen"
 )
 
 var (
@@ -21,12 +19,28 @@ var (
 )
 
 func main() {
-	var typeName string
+	var typeNames string
 	var output string
+	var tags string
+	var decoder bool
+	var fallible bool
+	var check bool
+	var jsonOutput bool
+	var encoderFuncs funcMappingFlag
+	var timeFormatterFuncs funcMappingFlag
+	var timeParserFuncs funcMappingFlag
 	var showVersion bool
 
-	flag.StringVar(&typeName, "type", "", "[mandatory] a type name")
-	flag.StringVar(&output, "output", "", `[optional] output file name (default "srcdir/<type>_gen.go")`)
+	flag.StringVar(&typeNames, "type", "", "[optional] a type name; multiple types can be given as comma-separated (default the structs that have //taqc:generate comment)")
+	flag.StringVar(&output, "output", "", `[optional] output file name; "-" means the standard output (default "srcdir/<type>_gen.go")`)
+	flag.StringVar(&tags, "tags", "", "[optional] comma-separated build tags to apply on loading the packages")
+	flag.BoolVar(&decoder, "decoder", false, "[optional] generate FromQueryParameters(url.Values) error method as well")
+	flag.BoolVar(&fallible, "fallible", false, "[optional] generate ToQueryParameters() (url.Values, error) method, which returns the errors of the encoders, MarshalText(), and loading the time zones")
+	flag.Var(&encoderFuncs, "encoder", "[optional] a function to encode the values of a type, in the form of `Type=Func` (repeatable)")
+	flag.Var(&timeFormatterFuncs, "time-formatter", "[optional] a function to format time.Time for timeFormatter option, in the form of `name=Func` (repeatable)")
+	flag.Var(&timeParserFuncs, "time-parser", "[optional] a function to parse time.Time for timeFormatter option, in the form of `name=Func` (repeatable)")
+	flag.BoolVar(&check, "check", false, "[optional] check the generated files are up to date instead of writing them; it exits with non-zero status and shows the diff if not")
+	flag.BoolVar(&jsonOutput, "json", false, "[optional] report the problems of the fields in JSON to the standard error")
 	flag.BoolVar(&showVersion, "version", false, "show the version information")
 
 	flag.Parse()
@@ -40,176 +54,135 @@ func main() {
 		return
 	}
 
-	args := flag.Args()
-	if len(args) <= 0 {
-		args = []string{"."}
+	cfg := gen.Config{
+		TypeNames:      splitList(typeNames),
+		Patterns:       flag.Args(),
+		Tags:           splitList(tags),
+		Decoder:        decoder,
+		Fallible:       fallible,
+		Encoders:       encoderFuncs,
+		TimeFormatters: timeFormatterFuncs,
+		TimeParsers:    timeParserFuncs,
+		Args:           headerArgs(os.Args[1:]),
 	}
 
-	pkg, err := internal.ParsePackage(args)
-	if err != nil {
-		log.Fatal(fmt.Errorf("[error] failed to parse a package: %w", err))
+	if check {
+		if output == "-" {
+			log.Fatal("[error] -check cannot be given with -output -")
+		}
+		drifts, err := gen.Check(cfg, output)
+		if err != nil {
+			exitWithError(err, jsonOutput)
+		}
+		for _, drift := range drifts {
+			fmt.Print(drift.Diff)
+		}
+		if len(drifts) > 0 {
+			for _, drift := range drifts {
+				if drift.Orphan {
+					log.Printf("[error] %s is generated for the type that doesn't exist anymore; remove that", drift.Path)
+				} else {
+					log.Printf("[error] %s is out of date; regenerate that by go generate", drift.Path)
+				}
+			}
+			os.Exit(1)
+		}
+		return
 	}
 
-	astFiles, err := internal.ParseFiles(pkg.GoFiles)
+	files, err := gen.GenerateFiles(cfg)
 	if err != nil {
-		log.Fatal(fmt.Errorf("[error] failed to parse a file: %w", err))
+		exitWithError(err, jsonOutput)
 	}
-
-	fields, err := internal.CollectQueryParameterFieldsFromAST(typeName, astFiles)
-	if err != nil {
-		log.Fatal(fmt.Errorf("[error] failed to collect fields from files: %w", err))
+	if output != "" && len(files) > 1 {
+		log.Fatalf("[error] -output cannot be given when it generates the files for %d packages", len(files))
 	}
 
-	rootStmt := g.NewRoot(
-		g.NewComment(fmt.Sprintf(" Code generated by taqc %s; DO NOT EDIT.", strings.Join(os.Args[1:], " "))),
-		g.NewNewline(),
-		g.NewPackage(pkg.Name),
-		g.NewNewline(),
-	)
-
-	timeUsed := false
-
-	f := g.NewFunc(g.NewFuncReceiver("v", "*"+typeName), g.NewFuncSignature("ToQueryParameters").ReturnTypes("url.Values")).AddStatements(
-		g.NewRawStatement("qp := url.Values{}"),
-	)
-	for _, field := range fields {
-		fieldName := field.FieldName
-		paramName := field.ParamName
-		fieldType := field.FieldType
-		switch fieldType {
-		case "string":
-			f = f.AddStatements(g.NewRawStatementf(`qp.Set("%s", v.%s)`, paramName, fieldName))
-		case "int64":
-			f = f.AddStatements(g.NewRawStatementf(`qp.Set("%s", fmt.Sprintf("%%d", v.%s))`, paramName, fieldName))
-		case "float64":
-			f = f.AddStatements(g.NewRawStatementf(`qp.Set("%s", fmt.Sprintf("%%f", v.%s))`, paramName, fieldName))
-		case "bool":
-			f = f.AddStatements(
-				g.NewIf(
-					fmt.Sprintf("v.%s", fieldName),
-					g.NewRawStatementf(`qp.Set("%s", "1")`, paramName),
-				),
-			)
-		case "time.Time":
-			timeUsed = true
-			timeFormatter, _ := field.TimeFormatterStmt.Generate(0)
-			f = f.AddStatements(g.NewRawStatementf(`qp.Set("%s", %s(v.%s))`, paramName, strings.TrimRight(timeFormatter, "\n"), fieldName))
-		case "*string":
-			f = f.AddStatements(
-				g.NewIf(
-					fmt.Sprintf("v.%s != nil", fieldName),
-					g.NewRawStatementf(`qp.Set("%s", *v.%s)`, paramName, fieldName),
-				),
-			)
-		case "*int64":
-			f = f.AddStatements(
-				g.NewIf(
-					fmt.Sprintf("v.%s != nil", fieldName),
-					g.NewRawStatementf(`qp.Set("%s", fmt.Sprintf("%%d", *v.%s))`, paramName, fieldName),
-				),
-			)
-		case "*float64":
-			f = f.AddStatements(
-				g.NewIf(
-					fmt.Sprintf("v.%s != nil", fieldName),
-					g.NewRawStatementf(`qp.Set("%s", fmt.Sprintf("%%f", *v.%s))`, paramName, fieldName),
-				),
-			)
-		case "*bool":
-			f = f.AddStatements(
-				g.NewIf(
-					fmt.Sprintf("v.%s != nil && *v.%s", fieldName, fieldName),
-					g.NewRawStatementf(`qp.Set("%s", "1")`, paramName),
-				),
-			)
-		case "*time.Time":
-			timeUsed = true
-			timeFormatter, _ := field.TimeFormatterStmt.Generate(0)
-			f = f.AddStatements(
-				g.NewIf(
-					fmt.Sprintf("v.%s != nil", fieldName),
-					g.NewRawStatementf(`qp.Set("%s", %s(*v.%s))`, paramName, strings.TrimRight(timeFormatter, "\n"), fieldName),
-				),
-			)
-		case "[]string":
-			sliceFieldName := strcase.ToLowerCamel(fmt.Sprintf("%s_slice", fieldName))
-			f = f.AddStatements(
-				g.NewRawStatementf("%s := v.%s", sliceFieldName, fieldName),
-				g.NewFor(
-					fmt.Sprintf("i := 0; i < len(%s); i++", sliceFieldName),
-					g.NewRawStatementf(`qp.Add("%s", %s[i])`, paramName, sliceFieldName),
-				),
-			)
-		case "[]int64":
-			sliceFieldName := strcase.ToLowerCamel(fmt.Sprintf("%s_slice", fieldName))
-			f = f.AddStatements(
-				g.NewRawStatementf("%s := v.%s", sliceFieldName, fieldName),
-				g.NewFor(
-					fmt.Sprintf("i := 0; i < len(%s); i++", sliceFieldName),
-					g.NewRawStatementf(`qp.Add("%s", fmt.Sprintf("%%d", %s[i]))`, paramName, sliceFieldName),
-				),
-			)
-		case "[]float64":
-			sliceFieldName := strcase.ToLowerCamel(fmt.Sprintf("%s_slice", fieldName))
-			f = f.AddStatements(
-				g.NewRawStatementf("%s := v.%s", sliceFieldName, fieldName),
-				g.NewFor(
-					fmt.Sprintf("i := 0; i < len(%s); i++", sliceFieldName),
-					g.NewRawStatementf(`qp.Add("%s", fmt.Sprintf("%%f", %s[i]))`, paramName, sliceFieldName),
-				),
-			)
-		case "[]time.Time":
-			timeUsed = true
-			sliceFieldName := strcase.ToLowerCamel(fmt.Sprintf("%s_slice", fieldName))
-			timeFormatter, _ := field.TimeFormatterStmt.Generate(0)
-			f = f.AddStatements(
-				g.NewRawStatementf("%s := v.%s", sliceFieldName, fieldName),
-				g.NewFor(
-					fmt.Sprintf("i := 0; i < len(%s); i++", sliceFieldName),
-					g.NewRawStatementf(`qp.Add("%s", %s(%s[i]))`, paramName, strings.TrimRight(timeFormatter, "\n"), sliceFieldName),
-				),
-			)
-		default:
-			log.Fatalf("[error] unsupported field type: %s", fieldType)
+	for _, file := range files {
+		if output == "-" {
+			_, err = os.Stdout.Write(file.Code)
+			if err != nil {
+				log.Fatal(fmt.Errorf("[error] failed output generated code to the standard output: %w", err))
+			}
+			continue
 		}
-	}
 
-	f = f.AddStatements(g.NewReturnStatement("qp"))
+		filename := file.Path
+		if output != "" {
+			filename = output
+		}
+		err = ioutil.WriteFile(filename, file.Code, 0644)
+		if err != nil {
+			log.Fatal(fmt.Errorf("[error] failed output generated code to a file: %w", err))
+		}
+	}
+}
 
-	imports := g.NewImport("fmt", "net/url")
-	if timeUsed {
-		imports = imports.AddImports("time")
+// exitWithError reports the error and exits. The problems of the fields are reported one per line in the form of `file:line:col: message`,
+// or as a JSON array when jsonOutput is true.
+func exitWithError(err error, jsonOutput bool) {
+	var diagnostics gen.Diagnostics
+	if !errors.As(err, &diagnostics) {
+		log.Fatal(fmt.Errorf("[error] %w", err))
 	}
 
-	code, err := rootStmt.AddStatements(imports, f).Gofmt("-s").Generate(0)
-	if err != nil {
-		log.Fatalf("[error] failed to generate code: %s", err)
+	if jsonOutput {
+		diagnosticsJSON, _ := json.Marshal(diagnostics)
+		fmt.Fprintf(os.Stderr, "%s\n", diagnosticsJSON)
+	} else {
+		for _, diagnostic := range diagnostics {
+			fmt.Fprintln(os.Stderr, diagnostic.Error())
+		}
 	}
+	os.Exit(1)
+}
 
-	err = ioutil.WriteFile(getFilenameToGenerate(args, typeName, output), []byte(code), 0644)
-	if err != nil {
-		log.Fatal(fmt.Errorf("[error] failed output generated code to a file: %w", err))
+// headerArgs returns the arguments to note in the header of the generated code. The flags that don't affect the code (i.e. `-check` and `-json`) are excluded,
+// so that the header of the code which is generated for the check is the same as the file's one.
+func headerArgs(args []string) []string {
+	filtered := make([]string, 0, len(args))
+	for _, arg := range args {
+		name := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0]
+		if strings.HasPrefix(arg, "-") && (name == "check" || name == "json") {
+			continue
+		}
+		filtered = append(filtered, arg)
 	}
+	return filtered
 }
 
-func isDirectory(name string) bool {
-	info, err := os.Stat(name)
-	if err != nil {
-		log.Fatal(err)
+// splitList splits given comma-separated value into the items.
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
 	}
-	return info.IsDir()
+	return items
 }
 
-func getFilenameToGenerate(args []string, typeName string, output string) string {
-	if output != "" {
-		return output
+// funcMappingFlag is the mapping from a key to the name of a function, which comes from the repeatable options in the form of `key=Func`;
+// e.g. the mapping from a type to the function that encodes a value of that type by `-encoder` options.
+type funcMappingFlag map[string]string
+
+func (f *funcMappingFlag) String() string {
+	mappings := make([]string, 0, len(*f))
+	for key, funcName := range *f {
+		mappings = append(mappings, key+"="+funcName)
 	}
+	return strings.Join(mappings, ",")
+}
 
-	var dir string
-	if len(args) == 1 && isDirectory(args[0]) {
-		dir = args[0]
-	} else {
-		dir = filepath.Dir(args[0])
+func (f *funcMappingFlag) Set(value string) error {
+	mapping := strings.SplitN(value, "=", 2)
+	if len(mapping) != 2 || strings.TrimSpace(mapping[0]) == "" || strings.TrimSpace(mapping[1]) == "" {
+		return fmt.Errorf("the mapping must be in the form of `key=Func` [given=%s]", value)
+	}
+	if *f == nil {
+		*f = funcMappingFlag{}
 	}
-	return fmt.Sprintf("%s/%s_gen.go", dir, strcase.ToSnake(typeName))
+	(*f)[strings.TrimSpace(mapping[0])] = strings.TrimSpace(mapping[1])
+	return nil
 }
@@ -5,26 +5,51 @@ import (
 	"fmt"
 	"net/url"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/moznion/taqc/internal"
 )
 
 var (
-	ErrNilValueGiven             = errors.New("given value is nil")
-	ErrQueryParameterNameIsEmpty = errors.New("query parameter name is empty in a tag")
-	ErrUnsupportedFieldType      = errors.New("unsupported filed type has come")
-	ErrUnsupportedUnixTimeUnit   = errors.New("unsupported unix time unit has given")
+	ErrNilValueGiven               = errors.New("given value is nil")
+	ErrQueryParameterNameIsEmpty   = errors.New("query parameter name is empty in a tag")
+	ErrUnexportedField             = errors.New("unexported field cannot have a tag")
+	ErrUnsupportedFieldType        = errors.New("unsupported filed type has come")
+	ErrUnsupportedUnixTimeUnit     = errors.New("unsupported unix time unit has given")
+	ErrMalformedTag                = internal.ErrMalformedTag
+	ErrUnknownTagOption            = internal.ErrUnknownTagOption
+	ErrDuplicatedTagOption         = internal.ErrDuplicatedTagOption
+	ErrUnsupportedFloatFormat      = internal.ErrUnsupportedFloatFormat
+	ErrUnsupportedNestStyle        = internal.ErrUnsupportedNestStyle
+	ErrCyclicNestedStruct          = internal.ErrCyclicNestedStruct
+	ErrAmbiguousQueryParameter     = internal.ErrAmbiguousQueryParameter
+	ErrUnsupportedMapStyle         = internal.ErrUnsupportedMapStyle
+	ErrUnsupportedCollectionFormat = internal.ErrUnsupportedCollectionFormat
+	ErrUnsupportedIndexStyle       = internal.ErrUnsupportedIndexStyle
+	ErrUnsupportedDurationUnit     = internal.ErrUnsupportedDurationUnit
+	ErrUnsupportedDurationFormat   = internal.ErrUnsupportedDurationFormat
+	ErrUnknownTimeZone             = internal.ErrUnknownTimeZone
+	ErrUnknownTimeFormatter        = errors.New("unknown time formatter has given")
+	ErrUnsupportedBoolFormat       = internal.ErrUnsupportedBoolFormat
 )
 
 // ConvertToQueryParams converts given structure to the query parameters according to the custom tags.
 //
 // When a field of the structure has `taqc` tag, it converts a value of that field to query parameter.
-// Currently, it supports the following field types: `string`, `int64`, `float64`, `bool`, `*string`, `*int64`, `*float64`, `*bool`, `[]string`, `[]int64`, `[]float64`, `time.Time`, `*time.Time`, and `[]time.Time`.
+// Currently, it supports the following field types: `string`, integer types (`int`, `int8`, `int16`, `int32`, `int64`, `uint`, `uint8`, `uint16`, `uint32`, and `uint64`),
+// float types (`float32` and `float64`), `bool`, and `time.Time`. It also supports the pointers of them and the slices of them.
 // If the bool field is `true`, the query parameter becomes `param_name=1`. Else, it omits the parameter.
 // And when the pointer value is `nil`, it omits the parameter.
 //
+// `boolFormat` option changes the encoding of a bool: `numeric` makes `1` and `0`, `truefalse` makes `true` and `false`, and `yesno` makes `yes` and `no`;
+// these encode `false` explicitly unless `omitempty` option is given. `presence` makes a bare flag for `true` (e.g. `?verbose`) and omits `false`.
+// Since `url.Values.Encode()` renders that as `verbose=`, use `EncodeQueryParams()` to render the bare flag.
+// The elements of `[]bool` are encoded in the same manner (`1` and `0` by default); `presence` doesn't support that.
+//
 // This library supports the `time.Time` fields. By default, it encodes that timestamp by `Time#Unix()`.
 // If you want to encode it by another unix time format, you can use `unixTimeUnit` custom tag value.
 // For example:
@@ -50,123 +75,710 @@ var (
 //
 // then, it encodes the timestamp by `Time#Format()` with given layout.
 //
+// `timeLayout` also accepts the names of the layout constants of `time` package: e.g. `timeLayout=RFC3339`, `timeLayout=DateOnly`, and `timeLayout=Kitchen`.
+//
 // NOTE: `timeLayout` takes priority over `unixTimeUnit`. This means it uses `timeLayout` option even if you put them together.
-func ConvertToQueryParams(v interface{}) (url.Values, error) {
+//
+// When neither of them fits, `RegisterTimeFormatter()` registers an arbitrary formatter by name, and `timeFormatter` option refers to that
+// (e.g. `taqc:"foo, timeFormatter=unixFrac"`). `timeFormatter` takes priority over both of the options, and an unregistered name causes an error (`ErrUnknownTimeFormatter`).
+//
+// `timeZone` option converts the timestamp into the location before formatting that by `timeLayout`; that is `UTC`, `Local`, or an IANA time zone name
+// (e.g. `Asia/Tokyo`). The zone data is embedded by `time/tzdata`, and an unknown zone causes an error (`ErrUnknownTimeZone`).
+//
+// A `time.Duration` field is encoded as the integer of nanoseconds by default. `durationUnit` option (`ns`, `us`, `ms`, `s`, `m`, or `h`)
+// encodes that as the integer in the unit (truncated toward zero), and `durationFormat=go` encodes that by `Duration#String()` (e.g. `1m30s`).
+//
+// A float field is encoded in the same manner as `fmt.Sprintf("%f")` by default. `floatFormat` option (`shortest`, `fixed`, or `exponent`)
+// and `floatPrecision` option (the number of digits) change that; e.g. `taqc:"foo, floatFormat=shortest"` encodes `123.456` as is.
+//
+// A non-pointer field is always encoded even if that is empty. `omitempty` option omits the parameter when the value is empty
+// (i.e. "", 0, false, nil pointer, or zero-length slice), and `omitzero` option omits that when the value is zero
+// (i.e. `IsZero()` returns true, or the value is the zero value of the type).
+// `keepEmpty` option on a slice field encodes a non-nil zero-length slice as a blank parameter (`param_name=`) to distinguish that from a nil slice.
+//
+// An option value can be quoted by single quotes (or each character can be escaped by a backslash) to contain commas, e.g.
+//
+// 	type Query struct {
+// 		Foo time.Time `taqc:"foo, timeLayout='Mon, 02 Jan 2006 15:04:05 MST'"` // RFC1123 layout
+// 	}
+//
+// A struct field or a pointer of struct field that has `inline` option is flattened; the tagged fields of that struct are encoded recursively.
+// `prefix` option prepends its value to the names of the nested parameters, and `nestStyle` option composes those names
+// from the parameter name of the `inline` field (`dot` makes `filter.status` and `bracket` makes `filter[status]`). e.g.
+//
+// 	type Query struct {
+// 		Filter *Filter `taqc:"filter, inline, prefix=filter."`
+// 		Page   Page    `taqc:"page, inline, nestStyle=bracket"`
+// 	}
+//
+// When the pointer of struct is `nil`, it omits all the nested parameters. A cycle of the nested structs causes an error.
+//
+// A slice of struct (or pointer of struct) field that has `inline` option encodes the tagged fields of each element with the index of that;
+// e.g. `items[0].sku=A&items[0].qty=2&items[1].sku=B`. `indexStyle` option (`bracket` makes `items[0]` and `dot` makes `items.0`) and
+// `nestStyle` option (`dot` makes `items[0].sku` and `bracket` makes `items[0][sku]`) change that notation. A nil element is omitted.
+//
+// A slice field is encoded as the repeated parameters (e.g. `ids=1&ids=2`) by default. `collectionFormat` option changes that;
+// `csv` makes `ids=1,2`, `ssv` makes `ids=1 2`, `pipes` makes `ids=1|2`, `brackets` makes `ids[]=1&ids[]=2`, and `indexed` makes `ids[0]=1&ids[1]=2`.
+// For `csv`, `ssv`, and `pipes`, `%` and the delimiter in each value are percent-encoded (e.g. `a,b` becomes `a%2Cb` for `csv`).
+//
+// A map field whose key is a string is encoded as `param_name[key]=value` (i.e. OpenAPI deepObject style) in the order of the keys.
+// The value can be the types above and the slices of them. `mapStyle=flat` option encodes that as `key=value`,
+// and `prefix` option prepends its value to the keys (e.g. `taqc:"filter, prefix=filter_"` makes `filter_key=value`).
+//
+// The encoders that are registered by `RegisterEncoder()` take priority over the built-in encoding; e.g.
+//
+// 	taqc.RegisterEncoder(reflect.TypeOf(Money{}), func(v reflect.Value) ([]string, error) {
+// 		m := v.Interface().(Money)
+// 		return []string{m.String()}, nil
+// 	})
+//
+// A field whose type implements `encoding.TextMarshaler` is encoded by `MarshalText()`, and otherwise a field whose type implements `fmt.Stringer`
+// is encoded by `String()`; these take priority over the encoding by the underlying kind. The pointers and the slices of such types are also supported,
+// and an error from `MarshalText()` is returned. The types of `time` package (e.g. `time.Time` and `time.Month`) keep the built-in encoding.
+//
+// The tagged fields of an embedded struct (or a pointer of struct) that has no tag are promoted as if they were the fields of the parent.
+// In the manner of Go, a parameter of the parent shadows the parameter that has the same name in the embedded struct,
+// and the parameters that have the same name at the same depth cause an error (`ErrAmbiguousQueryParameter`).
+//
+// An unknown option, a duplicated option, and a malformed tag value cause an error.
+//
+// `v` must be a non-nil pointer of the structure; otherwise it returns `ErrNonPointerValueGiven` or `ErrNilValueGiven`.
+// The error of a field is returned as `*FieldError` that has the path of the field (e.g. `Filter.Status` or `Items[1].SKU`), the parameter name,
+// and the type of that; the cause (e.g. `ErrUnsupportedFieldType`) is wrapped, so `errors.Is()` works with that.
+// An unexported field that has the tag causes `ErrUnexportedField`.
+// By default, it stops at the first error. `CollectAllErrors()` option makes it return the errors of all the fields as `FieldErrors`.
+func ConvertToQueryParams(v interface{}, opts ...ConvertOption) (url.Values, error) {
 	if v == nil {
 		return nil, ErrNilValueGiven
 	}
 
-	qp := url.Values{}
-
 	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Type().Elem().Kind() != reflect.Struct {
+		return nil, ErrNonPointerValueGiven
+	}
+	if rv.IsNil() {
+		return nil, ErrNilValueGiven
+	}
+
+	o := &convertOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	elem := rv.Elem()
-	for i := 0; i < elem.NumField(); i++ {
-		typeField := elem.Type().Field(i)
-		tag := typeField.Tag
-		tagValue, ok := tag.Lookup(internal.TagName)
-		if !ok { // nothing to do
+	plan, err := getEncoderPlan(elem.Type())
+	if err != nil {
+		if o.collectAllErrors {
+			return nil, err
+		}
+		return nil, firstError(err)
+	}
+
+	qp := url.Values{}
+	if o.collectAllErrors {
+		if errs := encodeStructFields(qp, elem, plan.fields); len(errs) > 0 {
+			return nil, errs
+		}
+		return qp, nil
+	}
+	for _, f := range plan.fields {
+		err := f.encode(qp, elem.Field(f.index))
+		if err != nil {
+			return nil, firstError(err)
+		}
+	}
+
+	return qp, nil
+}
+
+// ConvertOption is an option of `ConvertToQueryParams()`.
+type ConvertOption func(o *convertOptions)
+
+type convertOptions struct {
+	collectAllErrors bool
+}
+
+// CollectAllErrors makes `ConvertToQueryParams()` collect the errors of all the fields instead of stopping at the first one.
+// Then the error is `FieldErrors` that has all of them.
+func CollectAllErrors() ConvertOption {
+	return func(o *convertOptions) {
+		o.collectAllErrors = true
+	}
+}
+
+// encoderPlan is a compiled encoding plan of a structure type.
+// This is built once per type and cached, so each conversion doesn't have to parse the tags again.
+type encoderPlan struct {
+	fields []*fieldEncoder
+}
+
+// fieldEncoder encodes a field that has `taqc` tag. The error of the encoder is always FieldErrors.
+type fieldEncoder struct {
+	index  int
+	encode func(qp url.Values, field reflect.Value) error
+}
+
+// valueFormatter formats a non-container value to a query parameter value.
+type valueFormatter func(v reflect.Value) string
+
+var encoderPlans sync.Map // map[reflect.Type]*encoderPlan
+
+// registryMu makes the registrations (e.g. RegisterEncoder()) exclusive with the compilations of the encoding plans,
+// so that a plan that is compiled from the registries before a registration is never cached after that.
+var registryMu sync.RWMutex
+
+func getEncoderPlan(t reflect.Type) (*encoderPlan, error) {
+	if plan, ok := encoderPlans.Load(t); ok {
+		return plan.(*encoderPlan), nil
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	plan, err := compileEncoderPlan(t)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := encoderPlans.LoadOrStore(t, plan)
+	return actual.(*encoderPlan), nil
+}
+
+// updateRegistry runs given registration, and discards the compiled encoding plans; the plans are compiled again from the updated registries.
+func updateRegistry(register func()) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	register()
+	encoderPlans.Range(func(key, _ interface{}) bool {
+		encoderPlans.Delete(key)
+		return true
+	})
+}
+
+func compileEncoderPlan(t reflect.Type) (*encoderPlan, error) {
+	structFields, err := getStructFields(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encoderPlan{
+		fields: compileStructEncoders(structFields),
+	}, nil
+}
+
+// compileStructEncoders compiles the encoders of given fields of a structure.
+func compileStructEncoders(structFields []*structField) []*fieldEncoder {
+	fields := make([]*fieldEncoder, 0, len(structFields))
+	for _, structField := range structFields {
+		if structField.nestedType != nil {
+			fields = append(fields, &fieldEncoder{
+				index:  structField.index,
+				encode: compileNestedStructEncoder(structField),
+			})
+			continue
+		}
+		if structField.shadowed {
 			continue
 		}
 
-		splitTagValues := strings.Split(tagValue, ",")
-		paramName := strings.TrimSpace(splitTagValues[0])
-		if paramName == "" {
-			return nil, ErrQueryParameterNameIsEmpty
+		fields = append(fields, &fieldEncoder{
+			index:  structField.index,
+			encode: compileLeafEncoder(structField),
+		})
+	}
+
+	return fields
+}
+
+// compileLeafEncoder compiles the encoder of given field that is neither the field that has `inline` option nor the embedded structure,
+// except the slice of struct. The encoder returns the error as FieldErrors of the field.
+// When the field can't be encoded by its options (e.g. an unsupported option value), the encoder always returns that error.
+func compileLeafEncoder(structField *structField) func(qp url.Values, field reflect.Value) error {
+	opts, err := newFieldOptions(structField.parsedTag)
+	if err != nil {
+		errs := FieldErrors{newFieldError(structField, err)}
+		return func(qp url.Values, field reflect.Value) error {
+			return errs
 		}
+	}
 
-		timeLayout, unixTimeUnit := internal.ExtractTimeTag(splitTagValues[1:])
+	if structField.elemType != nil {
+		return compileSliceOfStructEncoder(structField)
+	}
 
-		timeFormatter := func(t time.Time) string {
-			return fmt.Sprintf("%d", t.Unix())
+	var encode func(qp url.Values, field reflect.Value) error
+	if structField.typ.Kind() == reflect.Map {
+		encode = compileMapEncoder(structField, opts)
+	} else {
+		encode = compileFieldEncoder(structField.paramName, structField.typ, opts)
+	}
+	return func(qp url.Values, field reflect.Value) error {
+		err := encode(qp, field)
+		if err != nil {
+			return FieldErrors{newFieldError(structField, err)}
 		}
-		if unixTimeUnit != "" {
-			unixTimeGetter, err := getUnixTimeGetter(unixTimeUnit)
-			if err != nil {
-				return nil, err
+		return nil
+	}
+}
+
+// encodeStructFields encodes the fields of given structure value by the encoders. This runs all the encoders, and returns all the errors of them as FieldErrors.
+func encodeStructFields(qp url.Values, v reflect.Value, fields []*fieldEncoder) FieldErrors {
+	var errs FieldErrors
+	for _, f := range fields {
+		err := f.encode(qp, v.Field(f.index))
+		if err != nil {
+			errs = append(errs, err.(FieldErrors)...)
+		}
+	}
+	return errs
+}
+
+// compileNestedStructEncoder compiles the encoder of the field that has `inline` option or the embedded structure.
+func compileNestedStructEncoder(structField *structField) func(qp url.Values, field reflect.Value) error {
+	children := compileStructEncoders(structField.nested)
+
+	encodeStruct := func(qp url.Values, field reflect.Value) error {
+		if errs := encodeStructFields(qp, field, children); len(errs) > 0 {
+			return errs
+		}
+		return nil
+	}
+	if !structField.isPtr {
+		return encodeStruct
+	}
+	return func(qp url.Values, field reflect.Value) error {
+		if field.IsNil() {
+			return nil
+		}
+		return encodeStruct(qp, field.Elem())
+	}
+}
+
+// compileSliceOfStructEncoder compiles the encoder of the slice of struct field that has `inline` option.
+// That encodes each element with the names that the index of the element is filled in; e.g. `items[0].sku`.
+// The indices are numbered only for the elements that have any parameter, so that the decoder, which stops at the first missing index, takes all of them.
+func compileSliceOfStructEncoder(structField *structField) func(qp url.Values, field reflect.Value) error {
+	children := compileStructEncoders(structField.elemFields)
+
+	return func(qp url.Values, field reflect.Value) error {
+		var errs FieldErrors
+		index := 0
+		for j := 0; j < field.Len(); j++ {
+			elem := field.Index(j)
+			if structField.isElemPtr {
+				if elem.IsNil() {
+					continue
+				}
+				elem = elem.Elem()
+			}
+
+			elemQP := url.Values{}
+			if elemErrs := encodeStructFields(elemQP, elem, children); len(elemErrs) > 0 {
+				errs = append(errs, fillFieldErrorIndex(elemErrs, j)...)
 			}
-			timeFormatter = func(t time.Time) string {
-				return fmt.Sprintf("%d", unixTimeGetter(t))
+			if len(elemQP) <= 0 {
+				continue
+			}
+			for paramName, values := range elemQP {
+				qp[internal.FillIndex(paramName, index)] = values
+			}
+			index++
+		}
+		if len(errs) > 0 {
+			return errs
+		}
+		return nil
+	}
+}
+
+// fieldOptions is the encoding options of a field, which come from the tag.
+type fieldOptions struct {
+	timeFormatter func(t time.Time) string
+	// durationUnit is the unit to encode `time.Duration` as an integer; 0 means `Duration.String()`.
+	durationUnit time.Duration
+	// boolFormat is the format to encode a bool.
+	boolFormat internal.BoolFormat
+	// floatFmt and floatPrec are the arguments for `strconv.FormatFloat()`.
+	floatFmt  byte
+	floatPrec int
+	// omitEmpty omits the parameter when the value is empty; i.e. "", 0, false, nil pointer, or zero-length slice.
+	omitEmpty bool
+	// omitZero omits the parameter when the value is zero; i.e. `IsZero()` returns true if the type has that method, or the value is the zero value of the type.
+	omitZero bool
+	// keepEmpty encodes a non-nil zero-length slice as a blank parameter (`param_name=`) to distinguish that from a nil slice.
+	keepEmpty bool
+	// collectionFormat is the format to encode a slice.
+	collectionFormat internal.CollectionFormat
+}
+
+func newFieldOptions(parsedTag *internal.Tag) (*fieldOptions, error) {
+	timeLayout, _ := parsedTag.Option("timeLayout")
+	timeLayout = internal.ResolveTimeLayout(timeLayout)
+	unixTimeUnit, _ := parsedTag.Option("unixTimeUnit")
+	timeZone, _ := parsedTag.Option("timeZone")
+
+	loc, err := internal.LoadTimeZone(timeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	timeFormatter := func(t time.Time) string {
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+	if unixTimeUnit != "" {
+		unixTimeGetter, err := getUnixTimeGetter(unixTimeUnit)
+		if err != nil {
+			return nil, err
+		}
+		timeFormatter = func(t time.Time) string {
+			return strconv.FormatInt(unixTimeGetter(t), 10)
+		}
+	}
+	if timeLayout != "" { // higher priority
+		timeFormatter = func(t time.Time) string {
+			if loc != nil {
+				t = t.In(loc)
 			}
+			return t.Format(timeLayout)
 		}
-		if timeLayout != "" { // higher priority
-			timeFormatter = func(t time.Time) string {
-				return t.Format(timeLayout)
+	}
+	if name, ok := parsedTag.Option("timeFormatter"); ok { // the highest priority
+		f, err := lookupTimeFormatter(name)
+		if err != nil {
+			return nil, err
+		}
+		timeFormatter = func(t time.Time) string {
+			if loc != nil {
+				t = t.In(loc)
 			}
+			return f.formatter(t)
 		}
+	}
+
+	floatFormat, _ := parsedTag.Option("floatFormat")
+	floatPrecision, _ := parsedTag.Option("floatPrecision")
+	floatFmt, floatPrec, err := internal.ParseFloatFormat(floatFormat, floatPrecision)
+	if err != nil {
+		return nil, err
+	}
+
+	durationFormat, _ := parsedTag.Option("durationFormat")
+	durationUnitValue, _ := parsedTag.Option("durationUnit")
+	durationUnit, err := internal.ParseDurationFormat(durationFormat, durationUnitValue)
+	if err != nil {
+		return nil, err
+	}
+
+	boolFormatValue, _ := parsedTag.Option("boolFormat")
+	boolFormat, err := internal.ParseBoolFormat(boolFormatValue)
+	if err != nil {
+		return nil, err
+	}
+
+	_, omitEmpty := parsedTag.Option("omitempty")
+	_, omitZero := parsedTag.Option("omitzero")
+	_, keepEmpty := parsedTag.Option("keepEmpty")
+
+	collectionFormatValue, _ := parsedTag.Option("collectionFormat")
+	collectionFormat, err := internal.ParseCollectionFormat(collectionFormatValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fieldOptions{
+		timeFormatter: timeFormatter,
+		durationUnit:  durationUnit,
+		boolFormat:    boolFormat,
+		floatFmt:      floatFmt,
+		floatPrec:     floatPrec,
+		omitEmpty:     omitEmpty,
+		omitZero:      omitZero,
+		keepEmpty:     keepEmpty,
+
+		collectionFormat: collectionFormat,
+	}, nil
+}
+
+func compileFieldEncoder(paramName string, fieldType reflect.Type, opts *fieldOptions) func(qp url.Values, field reflect.Value) error {
+	if encode := compileCustomEncoder(paramName, fieldType, opts); encode != nil {
+		return encode
+	}
 
-		field := elem.Field(i)
-		fieldKind := field.Kind()
-		switch fieldKind {
-		case reflect.String:
-			qp.Set(paramName, field.String())
-		case reflect.Int64:
-			qp.Set(paramName, fmt.Sprintf("%d", field.Int()))
-		case reflect.Float64:
-			qp.Set(paramName, fmt.Sprintf("%f", field.Float()))
-		case reflect.Bool:
-			if field.Bool() {
-				qp.Set(paramName, "1")
+	fieldKind := fieldType.Kind()
+	switch fieldKind {
+	case reflect.Bool:
+		formatter := getValueFormatter(fieldType, opts)
+		omitFalse := opts.boolFormat.OmitsFalse() || opts.omitEmpty || opts.omitZero
+		return func(qp url.Values, field reflect.Value) error {
+			if field.Bool() || !omitFalse {
+				qp.Set(paramName, formatter(field))
+			}
+			return nil
+		}
+	case reflect.Ptr:
+		elemType := fieldType.Elem()
+		if elemType.Kind() == reflect.Bool {
+			formatter := getValueFormatter(elemType, opts)
+			omitFalse := opts.boolFormat.OmitsFalse()
+			return func(qp url.Values, field reflect.Value) error {
+				if !field.IsNil() && (field.Elem().Bool() || !omitFalse) {
+					qp.Set(paramName, formatter(field.Elem()))
+				}
+				return nil
 			}
-		case reflect.Struct:
-			if field.Type().PkgPath() == "time" && field.Type().Name() == "Time" {
-				t := field.Interface().(time.Time)
-				qp.Set(paramName, timeFormatter(t))
-			} else {
-				return nil, fmt.Errorf("field type is %s: %w", fieldKind, ErrUnsupportedFieldType)
+		}
+
+		formatter := getValueFormatter(elemType, opts)
+		if formatter == nil {
+			err := fmt.Errorf("%s is unsupported: %w", elemType, ErrUnsupportedFieldType)
+			return func(qp url.Values, field reflect.Value) error {
+				if !field.IsNil() {
+					return err
+				}
+				return nil
 			}
-		case reflect.Ptr:
+		}
+		return func(qp url.Values, field reflect.Value) error {
 			if !field.IsNil() {
-				fieldElem := field.Elem()
-				switch fieldElem.Kind() {
-				case reflect.String:
-					qp.Set(paramName, fieldElem.String())
-				case reflect.Int64:
-					qp.Set(paramName, fmt.Sprintf("%d", fieldElem.Int()))
-				case reflect.Float64:
-					qp.Set(paramName, fmt.Sprintf("%f", fieldElem.Float()))
-				case reflect.Bool:
-					if fieldElem.Bool() {
-						qp.Set(paramName, "1")
-					}
-				case reflect.Struct:
-					if fieldElem.Type().PkgPath() == "time" && fieldElem.Type().Name() == "Time" {
-						t := fieldElem.Interface().(time.Time)
-						qp.Set(paramName, timeFormatter(t))
-					} else {
-						return nil, fmt.Errorf("field type is *%s: %w", fieldKind, ErrUnsupportedFieldType)
-					}
-				default:
-					return nil, fmt.Errorf("field type is *%s: %w", fieldKind, ErrUnsupportedFieldType)
+				qp.Set(paramName, formatter(field.Elem()))
+			}
+			return nil
+		}
+	case reflect.Slice:
+		formatter := getValueFormatter(fieldType.Elem(), opts)
+		if fieldType.Elem().Kind() == reflect.Bool && !opts.boolFormat.SupportsCollection() {
+			err := fmt.Errorf("%s for []bool: %w", opts.boolFormat, ErrUnsupportedBoolFormat)
+			return func(qp url.Values, field reflect.Value) error {
+				if field.Len() > 0 {
+					return err
+				}
+				return nil
+			}
+		}
+		if formatter == nil {
+			err := fmt.Errorf("%s is unsupported: %w", fieldType.Elem(), ErrUnsupportedFieldType)
+			return func(qp url.Values, field reflect.Value) error {
+				if field.Len() > 0 {
+					return err
 				}
+				return nil
+			}
+		}
+		encodeSlice, blankParamName := compileSliceEncoder(paramName, formatter, opts.collectionFormat)
+		return func(qp url.Values, field reflect.Value) error {
+			if field.Len() <= 0 && opts.keepEmpty && !opts.omitEmpty && !field.IsNil() {
+				qp.Set(blankParamName, "")
+				return nil
+			}
+			encodeSlice(qp, field)
+			return nil
+		}
+	default:
+		formatter := getValueFormatter(fieldType, opts)
+		if formatter == nil {
+			err := fmt.Errorf("%s is unsupported: %w", fieldType, ErrUnsupportedFieldType)
+			return func(qp url.Values, field reflect.Value) error {
+				return err
+			}
+		}
+		zeroChecker := getZeroChecker(fieldType)
+		return func(qp url.Values, field reflect.Value) error {
+			if opts.omitEmpty && isEmptyValue(field) {
+				return nil
+			}
+			if opts.omitZero && zeroChecker(field) {
+				return nil
+			}
+			qp.Set(paramName, formatter(field))
+			return nil
+		}
+	}
+}
+
+// compileSliceEncoder compiles the function that encodes a slice according to the collection format.
+// This returns the parameter name for the blank parameter of `keepEmpty` option as well.
+func compileSliceEncoder(paramName string, formatter valueFormatter, collectionFormat internal.CollectionFormat) (func(qp url.Values, field reflect.Value), string) {
+	switch collectionFormat {
+	case internal.CollectionFormatBrackets:
+		bracketsParamName := paramName + "[]"
+		return func(qp url.Values, field reflect.Value) {
+			for j := 0; j < field.Len(); j++ {
+				qp.Add(bracketsParamName, formatter(field.Index(j)))
 			}
-		case reflect.Slice:
+		}, bracketsParamName
+	case internal.CollectionFormatIndexed:
+		return func(qp url.Values, field reflect.Value) {
+			for j := 0; j < field.Len(); j++ {
+				qp.Set(paramName+"["+strconv.Itoa(j)+"]", formatter(field.Index(j)))
+			}
+		}, paramName
+	case internal.CollectionFormatCSV, internal.CollectionFormatSSV, internal.CollectionFormatPipes:
+		delimiter := collectionFormat.Delimiter()
+		return func(qp url.Values, field reflect.Value) {
 			l := field.Len()
+			if l <= 0 {
+				return
+			}
+			values := make([]string, l)
 			for j := 0; j < l; j++ {
-				item := field.Index(j)
-				switch item.Kind() {
-				case reflect.String:
-					qp.Add(paramName, item.String())
-				case reflect.Int64:
-					qp.Add(paramName, fmt.Sprintf("%d", item.Int()))
-				case reflect.Float64:
-					qp.Add(paramName, fmt.Sprintf("%f", item.Float()))
-				case reflect.Struct:
-					if item.Type().PkgPath() == "time" && item.Type().Name() == "Time" {
-						t := item.Interface().(time.Time)
-						qp.Add(paramName, timeFormatter(t))
-					} else {
-						return nil, fmt.Errorf("field type is []%s: %w", fieldKind, ErrUnsupportedFieldType)
-					}
-				default:
-					return nil, fmt.Errorf("field type is []%s: %w", fieldKind, ErrUnsupportedFieldType)
+				values[j] = collectionFormat.EscapeCollectionValue(formatter(field.Index(j)))
+			}
+			qp.Set(paramName, strings.Join(values, delimiter))
+		}, paramName
+	default:
+		return func(qp url.Values, field reflect.Value) {
+			for j := 0; j < field.Len(); j++ {
+				qp.Add(paramName, formatter(field.Index(j)))
+			}
+		}, paramName
+	}
+}
+
+// compileMapEncoder compiles the encoder of the map field. That encodes the entries in the order of the keys, so the result is deterministic.
+func compileMapEncoder(structField *structField, opts *fieldOptions) func(qp url.Values, field reflect.Value) error {
+	mapType := structField.typ
+	valueType := mapType.Elem()
+
+	var encodeValue func(qp url.Values, paramName string, v reflect.Value)
+	switch valueType.Kind() {
+	case reflect.Bool:
+		formatter := getValueFormatter(valueType, opts)
+		omitFalse := opts.boolFormat.OmitsFalse()
+		encodeValue = func(qp url.Values, paramName string, v reflect.Value) {
+			if v.Bool() || !omitFalse {
+				qp.Set(paramName, formatter(v))
+			}
+		}
+	case reflect.Slice:
+		if valueType.Elem().Kind() == reflect.Bool && !opts.boolFormat.SupportsCollection() {
+			break
+		}
+		if formatter := getValueFormatter(valueType.Elem(), opts); formatter != nil {
+			encodeValue = func(qp url.Values, paramName string, v reflect.Value) {
+				for j := 0; j < v.Len(); j++ {
+					qp.Add(paramName, formatter(v.Index(j)))
 				}
 			}
-		default:
-			return nil, fmt.Errorf("field type is %s: %w", fieldKind, ErrUnsupportedFieldType)
+		}
+	default:
+		if formatter := getValueFormatter(valueType, opts); formatter != nil {
+			encodeValue = func(qp url.Values, paramName string, v reflect.Value) {
+				qp.Set(paramName, formatter(v))
+			}
 		}
 	}
 
-	return qp, nil
+	if mapType.Key().Kind() != reflect.String || encodeValue == nil {
+		err := fmt.Errorf("%s is unsupported: %w", mapType, ErrUnsupportedFieldType)
+		return func(qp url.Values, field reflect.Value) error {
+			if field.Len() > 0 {
+				return err
+			}
+			return nil
+		}
+	}
+
+	prefix, suffix := structField.mapPrefix, structField.mapSuffix
+	return func(qp url.Values, field reflect.Value) error {
+		keys := field.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		for _, key := range keys {
+			encodeValue(qp, prefix+key.String()+suffix, field.MapIndex(key))
+		}
+		return nil
+	}
+}
+
+// isEmptyValue reports whether given value is empty in the manner of `omitempty` option.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+type zeroer interface {
+	IsZero() bool
+}
+
+var zeroerType = reflect.TypeOf((*zeroer)(nil)).Elem()
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// getZeroChecker returns the function that reports whether given value is zero in the manner of `omitzero` option.
+// If the type has `IsZero() bool` method (e.g. `time.Time`), that is used.
+func getZeroChecker(t reflect.Type) func(v reflect.Value) bool {
+	if t.Implements(zeroerType) {
+		return func(v reflect.Value) bool {
+			return v.Interface().(zeroer).IsZero()
+		}
+	}
+	return func(v reflect.Value) bool {
+		return v.IsZero()
+	}
+}
+
+// getValueFormatter returns the formatter for given type. If the type is not supported, this returns nil.
+func getValueFormatter(t reflect.Type, opts *fieldOptions) valueFormatter {
+	if t == durationType {
+		if opts.durationUnit == 0 {
+			return func(v reflect.Value) string {
+				return time.Duration(v.Int()).String()
+			}
+		}
+		return func(v reflect.Value) string {
+			return strconv.FormatInt(v.Int()/int64(opts.durationUnit), 10)
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(v reflect.Value) string {
+			return v.String()
+		}
+	case reflect.Bool:
+		trueValue, falseValue := opts.boolFormat.Values()
+		return func(v reflect.Value) string {
+			if v.Bool() {
+				return trueValue
+			}
+			return falseValue
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value) string {
+			return strconv.FormatInt(v.Int(), 10)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(v reflect.Value) string {
+			return strconv.FormatUint(v.Uint(), 10)
+		}
+	case reflect.Float32, reflect.Float64:
+		bitSize := t.Bits()
+		return func(v reflect.Value) string {
+			return strconv.FormatFloat(v.Float(), opts.floatFmt, opts.floatPrec, bitSize)
+		}
+	case reflect.Struct:
+		if t.PkgPath() == "time" && t.Name() == "Time" {
+			return func(v reflect.Value) string {
+				if v.CanAddr() { // avoid boxing the value
+					return opts.timeFormatter(*v.Addr().Interface().(*time.Time))
+				}
+				return opts.timeFormatter(v.Interface().(time.Time))
+			}
+		}
+	}
+	return nil
 }
 
 func getUnixTimeGetter(unixTimeUnit string) (func(t time.Time) int64, error) {
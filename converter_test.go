@@ -3,7 +3,8 @@ package taqc
 import (
 	"fmt"
 	"net/url"
-	"regexp"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -107,6 +108,71 @@ func TestConvertToQueryParams_ForPointerFields(t *testing.T) {
 	assert.EqualValues(t, url.Values{}, qp)
 }
 
+func TestConvertToQueryParams_ForNumericFields(t *testing.T) {
+	type Query struct {
+		Int        int       `taqc:"int"`
+		Int8       int8      `taqc:"int8"`
+		Int16      int16     `taqc:"int16"`
+		Int32      int32     `taqc:"int32"`
+		Uint       uint      `taqc:"uint"`
+		Uint8      uint8     `taqc:"uint8"`
+		Uint16     uint16    `taqc:"uint16"`
+		Uint32     uint32    `taqc:"uint32"`
+		Uint64     uint64    `taqc:"uint64"`
+		Float32    float32   `taqc:"float32"`
+		Shortest32 float32   `taqc:"shortest32, floatFormat=shortest"`
+		IntPtr     *int      `taqc:"intPtr"`
+		Uint32Ptr  *uint32   `taqc:"uint32Ptr"`
+		Float32Ptr *float32  `taqc:"float32Ptr"`
+		Ints       []int     `taqc:"ints"`
+		Uint64s    []uint64  `taqc:"uint64s"`
+		Float32s   []float32 `taqc:"float32s"`
+	}
+
+	i := -123
+	u := uint32(456)
+	f := float32(7.89)
+	qp, err := ConvertToQueryParams(&Query{
+		Int:        -1,
+		Int8:       -128,
+		Int16:      -32768,
+		Int32:      -2147483648,
+		Uint:       1,
+		Uint8:      255,
+		Uint16:     65535,
+		Uint32:     4294967295,
+		Uint64:     18446744073709551615,
+		Float32:    1.5,
+		Shortest32: 0.1,
+		IntPtr:     &i,
+		Uint32Ptr:  &u,
+		Float32Ptr: &f,
+		Ints:       []int{1, -2},
+		Uint64s:    []uint64{3, 4},
+		Float32s:   []float32{5.5, 6.25},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"int":        []string{"-1"},
+		"int8":       []string{"-128"},
+		"int16":      []string{"-32768"},
+		"int32":      []string{"-2147483648"},
+		"uint":       []string{"1"},
+		"uint8":      []string{"255"},
+		"uint16":     []string{"65535"},
+		"uint32":     []string{"4294967295"},
+		"uint64":     []string{"18446744073709551615"},
+		"float32":    []string{"1.500000"},
+		"shortest32": []string{"0.1"},
+		"intPtr":     []string{"-123"},
+		"uint32Ptr":  []string{"456"},
+		"float32Ptr": []string{"7.890000"},
+		"ints":       []string{"1", "-2"},
+		"uint64s":    []string{"3", "4"},
+		"float32s":   []string{"5.500000", "6.250000"},
+	}, qp)
+}
+
 func TestConvertToQueryParams_ShouldRaiseErrorWhenNilValueGiven(t *testing.T) {
 	_, err := ConvertToQueryParams(nil)
 	assert.ErrorIs(t, err, ErrNilValueGiven)
@@ -123,49 +189,51 @@ func TestConvertToQueryParams_ShouldRaiseErrorWhenTagParamValueIsEmpty(t *testin
 	assert.ErrorIs(t, err, ErrQueryParameterNameIsEmpty)
 }
 
+// unsupportedStruct is a struct type that has neither the built-in encoding nor the marshaler methods.
+type unsupportedStruct struct {
+	value int
+}
+
 func TestConvertToQueryParams_ShouldRaiseErrorWhenInvalidPrimitiveValue(t *testing.T) {
 	type Query struct {
-		Foo regexp.Regexp `taqc:"foo"`
+		Foo unsupportedStruct `taqc:"foo"`
 	}
 
 	_, err := ConvertToQueryParams(&Query{
-		Foo: *regexp.MustCompile(""),
+		Foo: unsupportedStruct{value: 1},
 	})
 	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
 }
 
 func TestConvertToQueryParams_ShouldRaiseErrorWhenInvalidPointerValue(t *testing.T) {
 	type Query struct {
-		Foo *regexp.Regexp `taqc:"foo"`
+		Foo *unsupportedStruct `taqc:"foo"`
 	}
 
 	_, err := ConvertToQueryParams(&Query{
-		Foo: regexp.MustCompile(""),
+		Foo: &unsupportedStruct{value: 1},
 	})
 	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
 }
 
 func TestConvertToQueryParams_ShouldRaiseErrorWhenInvalidSliceValue(t *testing.T) {
 	type Query struct {
-		Foo []bool `taqc:"foo"`
+		Foo []complex128 `taqc:"foo"`
 	}
 
 	_, err := ConvertToQueryParams(&Query{
-		Foo: []bool{true},
+		Foo: []complex128{1},
 	})
 	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
 }
 
 func TestConvertToQueryParams_ShouldRaiseErrorWhenInvalidStructSliceValue(t *testing.T) {
 	type Query struct {
-		Foo []regexp.Regexp `taqc:"foo"`
+		Foo []unsupportedStruct `taqc:"foo"`
 	}
 
 	_, err := ConvertToQueryParams(&Query{
-		Foo: []regexp.Regexp{func() regexp.Regexp {
-			r := regexp.MustCompile("")
-			return *r
-		}()},
+		Foo: []unsupportedStruct{{value: 1}},
 	})
 	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
 }
@@ -333,3 +401,804 @@ func TestConvertToQueryParams_WithUnsupportedPointerFieldType(t *testing.T) {
 	})
 	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
 }
+
+func TestConvertToQueryParams_WithQuotedTimeLayout(t *testing.T) {
+	type Query struct {
+		RFC1123        time.Time `taqc:"rfc1123, timeLayout='Mon, 02 Jan 2006 15:04:05 MST'"`
+		EscapedRFC1123 time.Time `taqc:"escaped_rfc1123, timeLayout=Mon\\, 02 Jan 2006 15:04:05 MST"`
+	}
+
+	now := time.Now()
+	qp, err := ConvertToQueryParams(&Query{
+		RFC1123:        now,
+		EscapedRFC1123: now,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"rfc1123":         []string{now.Format(time.RFC1123)},
+		"escaped_rfc1123": []string{now.Format(time.RFC1123)},
+	}, qp)
+}
+
+func TestConvertToQueryParams_ShouldRaiseErrorWithInvalidTagOption(t *testing.T) {
+	type Query1 struct {
+		RFC1123 time.Time `taqc:"rfc1123, timeLayout=Mon, 02 Jan 2006 15:04:05 MST"`
+	}
+	_, err := ConvertToQueryParams(&Query1{})
+	assert.ErrorIs(t, err, ErrUnknownTagOption)
+
+	type Query2 struct {
+		Foo time.Time `taqc:"foo, unixTimeUnit=sec, unixTimeUnit=millisec"`
+	}
+	_, err = ConvertToQueryParams(&Query2{})
+	assert.ErrorIs(t, err, ErrDuplicatedTagOption)
+
+	type Query3 struct {
+		Foo time.Time `taqc:"foo, timeLayout='2006"`
+	}
+	_, err = ConvertToQueryParams(&Query3{})
+	assert.ErrorIs(t, err, ErrMalformedTag)
+}
+
+func TestConvertToQueryParams_WithOmitEmpty(t *testing.T) {
+	type Query struct {
+		Foo  string    `taqc:"foo, omitempty"`
+		Bar  int64     `taqc:"bar, omitempty"`
+		Buz  float64   `taqc:"buz, omitempty"`
+		Time time.Time `taqc:"time, omitempty"`
+		Qux  []string  `taqc:"qux, omitempty, keepEmpty"`
+	}
+
+	qp, err := ConvertToQueryParams(&Query{
+		Qux: []string{},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"time": []string{fmt.Sprintf("%d", time.Time{}.Unix())}, // struct is never empty
+	}, qp)
+
+	qp, err = ConvertToQueryParams(&Query{
+		Foo:  "str-value",
+		Bar:  123,
+		Buz:  456.789,
+		Time: time.Unix(123, 0),
+		Qux:  []string{"s1"},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"foo":  []string{"str-value"},
+		"bar":  []string{"123"},
+		"buz":  []string{"456.789000"},
+		"time": []string{"123"},
+		"qux":  []string{"s1"},
+	}, qp)
+}
+
+func TestConvertToQueryParams_WithOmitZero(t *testing.T) {
+	type Query struct {
+		Foo     string     `taqc:"foo, omitzero"`
+		Bar     int64      `taqc:"bar, omitzero"`
+		Buz     float64    `taqc:"buz, omitzero"`
+		Time    time.Time  `taqc:"time, omitzero"`
+		TimePtr *time.Time `taqc:"timePtr, omitzero"`
+		Qux     []string   `taqc:"qux, omitzero, keepEmpty"`
+	}
+
+	zeroTime := time.Time{}
+	qp, err := ConvertToQueryParams(&Query{
+		Time:    time.Time{}.In(time.FixedZone("", 3600)), // `IsZero()` is respected
+		TimePtr: &zeroTime,
+		Qux:     nil,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"timePtr": []string{fmt.Sprintf("%d", zeroTime.Unix())}, // non-nil pointer is not zero
+	}, qp)
+
+	qp, err = ConvertToQueryParams(&Query{
+		Qux: []string{},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"qux": []string{""},
+	}, qp)
+}
+
+func TestConvertToQueryParams_WithKeepEmpty(t *testing.T) {
+	type Query struct {
+		Foo []string    `taqc:"foo, keepEmpty"`
+		Bar []int64     `taqc:"bar, keepEmpty"`
+		Buz []time.Time `taqc:"buz"`
+	}
+
+	qp, err := ConvertToQueryParams(&Query{
+		Foo: []string{},
+		Bar: nil,
+		Buz: []time.Time{},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"foo": []string{""},
+	}, qp)
+}
+
+func TestConvertToQueryParams_WithFloatFormat(t *testing.T) {
+	type Query struct {
+		Default   float64   `taqc:"default"`
+		Shortest  float64   `taqc:"shortest, floatFormat=shortest"`
+		Tiny      float64   `taqc:"tiny, floatFormat=shortest"`
+		Fixed     float64   `taqc:"fixed, floatFormat=fixed"`
+		Precision *float64  `taqc:"precision, floatPrecision=2"`
+		Exponent  []float64 `taqc:"exponent, floatFormat=exponent, floatPrecision=3"`
+	}
+
+	precision := 123.456
+	qp, err := ConvertToQueryParams(&Query{
+		Default:   123.456,
+		Shortest:  123.456,
+		Tiny:      1e-9,
+		Fixed:     1e-9,
+		Precision: &precision,
+		Exponent:  []float64{123.456, 0.5},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"default":   []string{"123.456000"},
+		"shortest":  []string{"123.456"},
+		"tiny":      []string{"1e-09"},
+		"fixed":     []string{"0.000000001"},
+		"precision": []string{"123.46"},
+		"exponent":  []string{"1.235e+02", "5.000e-01"},
+	}, qp)
+}
+
+func TestConvertToQueryParams_ShouldRaiseErrorWithInvalidFloatFormat(t *testing.T) {
+	type Query1 struct {
+		Foo float64 `taqc:"foo, floatFormat=INVALID"`
+	}
+	_, err := ConvertToQueryParams(&Query1{})
+	assert.ErrorIs(t, err, ErrUnsupportedFloatFormat)
+
+	type Query2 struct {
+		Foo float64 `taqc:"foo, floatPrecision=-1"`
+	}
+	_, err = ConvertToQueryParams(&Query2{})
+	assert.ErrorIs(t, err, ErrUnsupportedFloatFormat)
+}
+
+func TestConvertToQueryParams_ShouldReuseEncoderPlan(t *testing.T) {
+	type Query struct {
+		Foo string    `taqc:"foo"`
+		Bar time.Time `taqc:"bar, unixTimeUnit=millisec"`
+	}
+
+	now := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			qp, err := ConvertToQueryParams(&Query{
+				Foo: fmt.Sprintf("%d", i),
+				Bar: now,
+			})
+			assert.NoError(t, err)
+			assert.EqualValues(t, url.Values{
+				"foo": []string{fmt.Sprintf("%d", i)},
+				"bar": []string{fmt.Sprintf("%d", now.UnixMilli())},
+			}, qp)
+		}(i)
+	}
+	wg.Wait()
+
+	plan, ok := encoderPlans.Load(reflect.TypeOf(Query{}))
+	assert.True(t, ok)
+	assert.Len(t, plan.(*encoderPlan).fields, 2)
+}
+
+func BenchmarkConvertToQueryParams(b *testing.B) {
+	type Query struct {
+		Foo     string      `taqc:"foo"`
+		Bar     int64       `taqc:"bar"`
+		Buz     float64     `taqc:"buz"`
+		Qux     *bool       `taqc:"qux"`
+		Strings []string    `taqc:"strings"`
+		Time    time.Time   `taqc:"time, unixTimeUnit=millisec"`
+		Times   []time.Time `taqc:"times, timeLayout=2006-01-02T15:04:05Z07:00"`
+	}
+
+	qux := true
+	now := time.Now()
+	q := &Query{
+		Foo:     "str-value",
+		Bar:     123,
+		Buz:     456.789,
+		Qux:     &qux,
+		Strings: []string{"s1", "s2"},
+		Time:    now,
+		Times:   []time.Time{now, now},
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := ConvertToQueryParams(q)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestConvertToQueryParams_WithNestedStruct(t *testing.T) {
+	type Filter struct {
+		Status string `taqc:"status"`
+		Owner  string `taqc:"owner, omitempty"`
+	}
+	type Page struct {
+		Size   int64 `taqc:"size"`
+		Number int64 `taqc:"number"`
+	}
+	type Sort struct {
+		Key string `taqc:"key"`
+	}
+	type Query struct {
+		Filter    *Filter `taqc:"filter, inline, prefix=filter."`
+		Page      Page    `taqc:"page, inline, nestStyle=bracket"`
+		Sort      Sort    `taqc:"sort, inline"`
+		NilFilter *Filter `taqc:"nilFilter, inline, nestStyle=dot"`
+		DotFilter Filter  `taqc:"dotFilter, inline, nestStyle=dot"`
+	}
+
+	qp, err := ConvertToQueryParams(&Query{
+		Filter: &Filter{
+			Status: "open",
+			Owner:  "me",
+		},
+		Page: Page{
+			Size:   20,
+			Number: 2,
+		},
+		Sort: Sort{
+			Key: "name",
+		},
+		DotFilter: Filter{
+			Status: "closed",
+		},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"filter.status":    []string{"open"},
+		"filter.owner":     []string{"me"},
+		"page[size]":       []string{"20"},
+		"page[number]":     []string{"2"},
+		"key":              []string{"name"},
+		"dotFilter.status": []string{"closed"},
+	}, qp)
+}
+
+func TestConvertToQueryParams_WithDeeplyNestedStruct(t *testing.T) {
+	type Range struct {
+		From int64 `taqc:"from"`
+		To   int64 `taqc:"to"`
+	}
+	type Filter struct {
+		Price *Range `taqc:"price, inline, nestStyle=bracket"`
+	}
+	type Query struct {
+		Filter Filter `taqc:"filter, inline, nestStyle=bracket"`
+	}
+
+	qp, err := ConvertToQueryParams(&Query{
+		Filter: Filter{
+			Price: &Range{
+				From: 100,
+				To:   200,
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"filter[price][from]": []string{"100"},
+		"filter[price][to]":   []string{"200"},
+	}, qp)
+}
+
+func TestConvertToQueryParams_ShouldRaiseErrorWithInvalidNestedStruct(t *testing.T) {
+	type Node struct {
+		Name  string `taqc:"name"`
+		Child *Node  `taqc:"child, inline, nestStyle=dot"`
+	}
+	_, err := ConvertToQueryParams(&Node{})
+	assert.ErrorIs(t, err, ErrCyclicNestedStruct)
+
+	type Query1 struct {
+		Foo string `taqc:"foo, inline"`
+	}
+	_, err = ConvertToQueryParams(&Query1{})
+	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+
+	type Nested struct {
+		Bar string `taqc:"bar"`
+	}
+	type Query2 struct {
+		Nested Nested `taqc:"nested, inline, nestStyle=INVALID"`
+	}
+	_, err = ConvertToQueryParams(&Query2{})
+	assert.ErrorIs(t, err, ErrUnsupportedNestStyle)
+
+	type Query3 struct {
+		Nested Nested `taqc:"nested, inline, nestStyle=dot, prefix=n."`
+	}
+	_, err = ConvertToQueryParams(&Query3{})
+	assert.ErrorIs(t, err, ErrMalformedTag)
+}
+
+func TestConvertToQueryParams_WithSliceOfStruct(t *testing.T) {
+	type Part struct {
+		Name string `taqc:"name"`
+	}
+	type Item struct {
+		SKU   string   `taqc:"sku"`
+		Qty   int64    `taqc:"qty, omitempty"`
+		Tags  []string `taqc:"tags"`
+		Parts []Part   `taqc:"parts, inline, nestStyle=bracket"`
+	}
+	type Query struct {
+		Items    []Item  `taqc:"items, inline"`
+		PtrItems []*Item `taqc:"ptrItems, inline, indexStyle=dot"`
+		Empty    []Item  `taqc:"empty, inline"`
+	}
+
+	qp, err := ConvertToQueryParams(&Query{
+		Items: []Item{
+			{SKU: "A", Qty: 2, Tags: []string{"x", "y"}},
+			{SKU: "B", Parts: []Part{{Name: "p0"}, {Name: "p1"}}},
+		},
+		PtrItems: []*Item{
+			{SKU: "C", Qty: 1},
+			nil,
+			{SKU: "D", Qty: 3},
+		},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"items[0].sku":            []string{"A"},
+		"items[0].qty":            []string{"2"},
+		"items[0].tags":           []string{"x", "y"},
+		"items[1].sku":            []string{"B"},
+		"items[1].parts[0][name]": []string{"p0"},
+		"items[1].parts[1][name]": []string{"p1"},
+		"ptrItems.0.sku":          []string{"C"},
+		"ptrItems.0.qty":          []string{"1"},
+		"ptrItems.1.sku":          []string{"D"},
+		"ptrItems.1.qty":          []string{"3"},
+	}, qp)
+}
+
+func TestConvertToQueryParams_ShouldRaiseErrorWithInvalidSliceOfStruct(t *testing.T) {
+	type Item struct {
+		SKU string `taqc:"sku"`
+	}
+
+	type Query1 struct {
+		Items []Item `taqc:"items, inline, indexStyle=INVALID"`
+	}
+	_, err := ConvertToQueryParams(&Query1{})
+	assert.ErrorIs(t, err, ErrUnsupportedIndexStyle)
+
+	type Query2 struct {
+		Items []Item `taqc:"items, inline, prefix=i_"`
+	}
+	_, err = ConvertToQueryParams(&Query2{})
+	assert.ErrorIs(t, err, ErrMalformedTag)
+
+	type Query3 struct {
+		Items []string `taqc:"items, inline"`
+	}
+	_, err = ConvertToQueryParams(&Query3{})
+	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+
+	type Node struct {
+		Children []Node `taqc:"children, inline"`
+	}
+	_, err = ConvertToQueryParams(&Node{})
+	assert.ErrorIs(t, err, ErrCyclicNestedStruct)
+}
+
+type Pagination struct {
+	Limit  int64 `taqc:"limit"`
+	Offset int64 `taqc:"offset, omitempty"`
+}
+
+type Sorting struct {
+	Sort  string `taqc:"sort"`
+	Order string `taqc:"order"`
+}
+
+type pagination struct {
+	Page int64 `taqc:"page"`
+}
+
+func TestConvertToQueryParams_WithEmbeddedStruct(t *testing.T) {
+	type Query struct {
+		Pagination
+		*Sorting
+		pagination
+		Order string `taqc:"order"` // shadows Sorting.Order
+		Q     string `taqc:"q"`
+	}
+
+	qp, err := ConvertToQueryParams(&Query{
+		Pagination: Pagination{
+			Limit: 10,
+		},
+		Sorting: &Sorting{
+			Sort:  "name",
+			Order: "asc",
+		},
+		pagination: pagination{
+			Page: 3,
+		},
+		Order: "desc",
+		Q:     "foo",
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"limit": []string{"10"},
+		"sort":  []string{"name"},
+		"page":  []string{"3"},
+		"order": []string{"desc"},
+		"q":     []string{"foo"},
+	}, qp)
+
+	qp, err = ConvertToQueryParams(&Query{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"limit": []string{"0"},
+		"page":  []string{"0"},
+		"order": []string{""},
+		"q":     []string{""},
+	}, qp)
+}
+
+func TestConvertToQueryParams_WithTaggedEmbeddedStruct(t *testing.T) {
+	type Query struct {
+		Pagination `taqc:"page, inline, nestStyle=bracket"`
+	}
+
+	qp, err := ConvertToQueryParams(&Query{
+		Pagination: Pagination{
+			Limit:  10,
+			Offset: 20,
+		},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"page[limit]":  []string{"10"},
+		"page[offset]": []string{"20"},
+	}, qp)
+}
+
+func TestConvertToQueryParams_ShouldRaiseErrorWithAmbiguousParameter(t *testing.T) {
+	type OtherSorting struct {
+		Sort string `taqc:"sort"`
+	}
+	type Query1 struct {
+		Sorting
+		OtherSorting
+	}
+	_, err := ConvertToQueryParams(&Query1{})
+	assert.ErrorIs(t, err, ErrAmbiguousQueryParameter)
+	assert.EqualError(t, err, `field OtherSorting.Sort (parameter "sort", type string): "sort" is duplicated with the field Sorting.Sort: ambiguous query parameter has come`)
+
+	type Query2 struct {
+		Foo string `taqc:"foo"`
+		Bar string `taqc:"foo"`
+	}
+	_, err = ConvertToQueryParams(&Query2{})
+	assert.ErrorIs(t, err, ErrAmbiguousQueryParameter)
+	var fieldErr *FieldError
+	assert.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "Bar", fieldErr.Path)
+	assert.Equal(t, "foo", fieldErr.ParamName)
+	assert.Contains(t, fieldErr.Error(), "the field Foo")
+
+	// the shallower parameter resolves the ambiguity
+	type Query3 struct {
+		Sorting
+		OtherSorting
+		Sort string `taqc:"sort"`
+	}
+	_, err = ConvertToQueryParams(&Query3{})
+	assert.NoError(t, err)
+}
+
+func TestConvertToQueryParams_WithMap(t *testing.T) {
+	type Key string
+	type Query struct {
+		Filter   map[string]string    `taqc:"filter"`
+		Tags     map[string][]string  `taqc:"tags, mapStyle=deepObject"`
+		Counts   map[Key]int64        `taqc:"counts, mapStyle=flat"`
+		Prefixed map[string]float64   `taqc:"prefixed, prefix=p_, floatFormat=shortest"`
+		Flags    map[string]bool      `taqc:"flags"`
+		Times    map[string]time.Time `taqc:"times, unixTimeUnit=millisec"`
+		Nil      map[string]string    `taqc:"nil"`
+		Nested   struct {
+			Values map[string]uint8 `taqc:"values"`
+		} `taqc:"nested, inline, nestStyle=bracket"`
+	}
+
+	q := &Query{
+		Filter: map[string]string{
+			"status": "open",
+			"owner":  "me",
+		},
+		Tags: map[string][]string{
+			"label": {"bug", "help"},
+		},
+		Counts: map[Key]int64{
+			"b": 2,
+			"a": 1,
+		},
+		Prefixed: map[string]float64{
+			"ratio": 0.5,
+		},
+		Flags: map[string]bool{
+			"on":  true,
+			"off": false,
+		},
+		Times: map[string]time.Time{
+			"since": time.UnixMilli(1640000000123),
+		},
+	}
+	q.Nested.Values = map[string]uint8{"x": 255}
+
+	qp, err := ConvertToQueryParams(q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"filter[owner]":     []string{"me"},
+		"filter[status]":    []string{"open"},
+		"tags[label]":       []string{"bug", "help"},
+		"a":                 []string{"1"},
+		"b":                 []string{"2"},
+		"p_ratio":           []string{"0.5"},
+		"flags[on]":         []string{"1"},
+		"times[since]":      []string{"1640000000123"},
+		"nested[values][x]": []string{"255"},
+	}, qp)
+	assert.Equal(t, "a=1&b=2&filter%5Bowner%5D=me&filter%5Bstatus%5D=open&flags%5Bon%5D=1&nested%5Bvalues%5D%5Bx%5D=255&p_ratio=0.5&tags%5Blabel%5D=bug&tags%5Blabel%5D=help&times%5Bsince%5D=1640000000123", qp.Encode())
+}
+
+func TestConvertToQueryParams_WithUnsupportedMap(t *testing.T) {
+	type Query1 struct {
+		Foo map[int]string `taqc:"foo"`
+	}
+	_, err := ConvertToQueryParams(&Query1{Foo: map[int]string{1: "foo"}})
+	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+
+	type Query2 struct {
+		Foo map[string]*string `taqc:"foo"`
+	}
+	_, err = ConvertToQueryParams(&Query2{})
+	assert.NoError(t, err)
+	_, err = ConvertToQueryParams(&Query2{Foo: map[string]*string{"foo": nil}})
+	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+
+	type Query3 struct {
+		Foo map[string]string `taqc:"foo, mapStyle=INVALID"`
+	}
+	_, err = ConvertToQueryParams(&Query3{})
+	assert.ErrorIs(t, err, ErrUnsupportedMapStyle)
+}
+
+func TestConvertToQueryParams_WithCollectionFormat(t *testing.T) {
+	type Query struct {
+		Multi    []int64     `taqc:"multi, collectionFormat=multi"`
+		CSV      []string    `taqc:"csv, collectionFormat=csv"`
+		SSV      []float64   `taqc:"ssv, collectionFormat=ssv, floatFormat=shortest"`
+		Pipes    []string    `taqc:"pipes, collectionFormat=pipes"`
+		Brackets []uint8     `taqc:"brackets, collectionFormat=brackets"`
+		Indexed  []time.Time `taqc:"indexed, collectionFormat=indexed, unixTimeUnit=millisec"`
+		Empty    []string    `taqc:"empty, collectionFormat=csv"`
+		Kept     []string    `taqc:"kept, collectionFormat=brackets, keepEmpty"`
+	}
+
+	q := &Query{
+		Multi:    []int64{1, 2},
+		CSV:      []string{"a,b", "50%", "c"},
+		SSV:      []float64{1.5, 2},
+		Pipes:    []string{"a|b", "c d"},
+		Brackets: []uint8{3, 4},
+		Indexed:  []time.Time{time.UnixMilli(1640000000123), time.UnixMilli(1640000000456)},
+		Empty:    []string{},
+		Kept:     []string{},
+	}
+	qp, err := ConvertToQueryParams(q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"multi":      []string{"1", "2"},
+		"csv":        []string{"a%2Cb,50%25,c"},
+		"ssv":        []string{"1.5 2"},
+		"pipes":      []string{"a%7Cb|c d"},
+		"brackets[]": []string{"3", "4"},
+		"indexed[0]": []string{"1640000000123"},
+		"indexed[1]": []string{"1640000000456"},
+		"kept[]":     []string{""},
+	}, qp)
+
+	decoded := &Query{}
+	err = UnmarshalQueryParams(qp, decoded)
+	assert.NoError(t, err)
+	assert.EqualValues(t, q.Multi, decoded.Multi)
+	assert.EqualValues(t, q.CSV, decoded.CSV)
+	assert.EqualValues(t, q.SSV, decoded.SSV)
+	assert.EqualValues(t, q.Pipes, decoded.Pipes)
+	assert.EqualValues(t, q.Brackets, decoded.Brackets)
+	assert.Len(t, decoded.Indexed, 2)
+	assert.True(t, q.Indexed[1].Equal(decoded.Indexed[1]))
+	assert.Nil(t, decoded.Empty)
+	assert.EqualValues(t, []string{}, decoded.Kept)
+}
+
+func TestConvertToQueryParams_ShouldRaiseErrorWithUnsupportedCollectionFormat(t *testing.T) {
+	type Query struct {
+		Foo []string `taqc:"foo, collectionFormat=tsv"`
+	}
+	_, err := ConvertToQueryParams(&Query{})
+	assert.ErrorIs(t, err, ErrUnsupportedCollectionFormat)
+
+	err = UnmarshalQueryParams(url.Values{"foo": []string{"a"}}, &Query{})
+	assert.ErrorIs(t, err, ErrUnsupportedCollectionFormat)
+}
+
+func TestConvertToQueryParams_WithDuration(t *testing.T) {
+	type Query struct {
+		Default     time.Duration   `taqc:"default"`
+		Millisec    time.Duration   `taqc:"millisec, durationUnit=ms"`
+		Sec         time.Duration   `taqc:"sec, durationUnit=s"`
+		Min         time.Duration   `taqc:"min, durationUnit=m"`
+		Hour        time.Duration   `taqc:"hour, durationUnit=h"`
+		Go          time.Duration   `taqc:"go, durationFormat=go"`
+		Ptr         *time.Duration  `taqc:"ptr, durationUnit=s"`
+		NilPtr      *time.Duration  `taqc:"nil_ptr, durationUnit=s"`
+		Slice       []time.Duration `taqc:"slice, durationFormat=go, collectionFormat=csv"`
+		OmitEmpty   time.Duration   `taqc:"omit_empty, durationUnit=s, omitempty"`
+		Unomittable time.Duration   `taqc:"unomittable, durationFormat=go"`
+	}
+
+	d := 90*time.Minute + 30*time.Second + 500*time.Millisecond
+	qp, err := ConvertToQueryParams(&Query{
+		Default:  d,
+		Millisec: d,
+		Sec:      d,
+		Min:      d,
+		Hour:     d,
+		Go:       d,
+		Ptr:      &d,
+		Slice:    []time.Duration{time.Second, 90 * time.Second},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"default":     []string{"5430500000000"},
+		"millisec":    []string{"5430500"},
+		"sec":         []string{"5430"},
+		"min":         []string{"90"},
+		"hour":        []string{"1"},
+		"go":          []string{"1h30m30.5s"},
+		"ptr":         []string{"5430"},
+		"slice":       []string{"1s,1m30s"},
+		"unomittable": []string{"0s"},
+	}, qp)
+}
+
+func TestConvertToQueryParams_ShouldRaiseErrorWithInvalidDurationOption(t *testing.T) {
+	type Query1 struct {
+		Duration time.Duration `taqc:"duration, durationUnit=d"`
+	}
+	_, err := ConvertToQueryParams(&Query1{})
+	assert.ErrorIs(t, err, ErrUnsupportedDurationUnit)
+
+	type Query2 struct {
+		Duration *time.Duration `taqc:"duration, durationFormat=iso8601"`
+	}
+	_, err = ConvertToQueryParams(&Query2{})
+	assert.ErrorIs(t, err, ErrUnsupportedDurationFormat)
+
+	type Query3 struct {
+		Duration []time.Duration `taqc:"duration, durationFormat=go, durationUnit=s"`
+	}
+	_, err = ConvertToQueryParams(&Query3{})
+	assert.ErrorIs(t, err, ErrMalformedTag)
+}
+
+func TestConvertToQueryParams_WithTimeZone(t *testing.T) {
+	type Query struct {
+		UTC      time.Time  `taqc:"utc, timeLayout=2006-01-02T15:04:05Z07:00, timeZone=UTC"`
+		Tokyo    time.Time  `taqc:"tokyo, timeLayout=2006-01-02T15:04:05Z07:00, timeZone=Asia/Tokyo"`
+		TokyoPtr *time.Time `taqc:"tokyo_ptr, timeLayout=2006-01-02 15:04, timeZone=Asia/Tokyo"`
+		AsIs     time.Time  `taqc:"as_is, timeLayout=2006-01-02T15:04:05Z07:00"`
+		Unix     time.Time  `taqc:"unix, timeZone=Asia/Tokyo"`
+	}
+
+	ts := time.Date(2021, 12, 31, 20, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+	qp, err := ConvertToQueryParams(&Query{
+		UTC:      ts,
+		Tokyo:    ts,
+		TokyoPtr: &ts,
+		AsIs:     ts,
+		Unix:     ts,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"utc":       []string{"2022-01-01T01:30:00Z"},
+		"tokyo":     []string{"2022-01-01T10:30:00+09:00"},
+		"tokyo_ptr": []string{"2022-01-01 10:30"},
+		"as_is":     []string{"2021-12-31T20:30:00-05:00"},
+		"unix":      []string{fmt.Sprintf("%d", ts.Unix())},
+	}, qp)
+}
+
+func TestConvertToQueryParams_ShouldRaiseErrorWithUnknownTimeZone(t *testing.T) {
+	type Query struct {
+		Time time.Time `taqc:"time, timeLayout=2006-01-02, timeZone=Mars/Olympus_Mons"`
+	}
+	_, err := ConvertToQueryParams(&Query{})
+	assert.ErrorIs(t, err, ErrUnknownTimeZone)
+	assert.Contains(t, err.Error(), "Mars/Olympus_Mons")
+}
+
+func TestConvertToQueryParams_WithBoolFormat(t *testing.T) {
+	type Query struct {
+		Default      bool            `taqc:"default"`
+		DefaultFalse bool            `taqc:"default_false"`
+		Numeric      bool            `taqc:"numeric, boolFormat=numeric"`
+		TrueFalse    bool            `taqc:"true_false, boolFormat=truefalse"`
+		YesNo        bool            `taqc:"yes_no, boolFormat=yesno"`
+		OmitFalse    bool            `taqc:"omit_false, boolFormat=numeric, omitempty"`
+		Presence     bool            `taqc:"presence, boolFormat=presence"`
+		NoPresence   bool            `taqc:"no_presence, boolFormat=presence"`
+		Ptr          *bool           `taqc:"ptr, boolFormat=yesno"`
+		NilPtr       *bool           `taqc:"nil_ptr, boolFormat=yesno"`
+		Bools        []bool          `taqc:"bools"`
+		CSVBools     []bool          `taqc:"csv_bools, boolFormat=truefalse, collectionFormat=csv"`
+		Map          map[string]bool `taqc:"map, boolFormat=numeric"`
+	}
+
+	f := false
+	qp, err := ConvertToQueryParams(&Query{
+		Default:  true,
+		Numeric:  false,
+		Presence: true,
+		Ptr:      &f,
+		Bools:    []bool{true, false},
+		CSVBools: []bool{false, true},
+		Map:      map[string]bool{"a": true, "b": false},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"default":    []string{"1"},
+		"numeric":    []string{"0"},
+		"true_false": []string{"false"},
+		"yes_no":     []string{"no"},
+		"presence":   []string{""},
+		"ptr":        []string{"no"},
+		"bools":      []string{"1", "0"},
+		"csv_bools":  []string{"false,true"},
+		"map[a]":     []string{"1"},
+		"map[b]":     []string{"0"},
+	}, qp)
+}
+
+func TestConvertToQueryParams_ShouldRaiseErrorWithInvalidBoolFormat(t *testing.T) {
+	type Query struct {
+		Foo bool `taqc:"foo, boolFormat=onoff"`
+	}
+	_, err := ConvertToQueryParams(&Query{})
+	assert.ErrorIs(t, err, ErrUnsupportedBoolFormat)
+
+	type SliceQuery struct {
+		Foo []bool `taqc:"foo, boolFormat=presence"`
+	}
+	_, err = ConvertToQueryParams(&SliceQuery{Foo: []bool{true}})
+	assert.ErrorIs(t, err, ErrUnsupportedBoolFormat)
+}
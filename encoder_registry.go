@@ -0,0 +1,148 @@
+package taqc
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sync"
+)
+
+// EncoderFunc encodes a value of the registered type to the query parameter values.
+// When that returns no value, the parameter is omitted.
+type EncoderFunc func(v reflect.Value) ([]string, error)
+
+var customEncoders sync.Map // map[reflect.Type]EncoderFunc
+
+// RegisterEncoder registers the encoder for given type. `ConvertToQueryParams()` uses that in priority to the built-in encoding,
+// for the fields of that type, and the pointers and the slices of that.
+//
+// This should be called before the conversions (e.g. in `init()`); registering an encoder discards the compiled encoding plans,
+// and the conversions that start after the registration use the encoder even if the other conversions run concurrently.
+func RegisterEncoder(t reflect.Type, encoder EncoderFunc) {
+	updateRegistry(func() {
+		customEncoders.Store(t, encoder)
+	})
+}
+
+// lookupEncoder returns the registered encoder for given type.
+// If there is no registered encoder, this returns the encoder that uses `encoding.TextMarshaler` or `fmt.Stringer` of the type.
+func lookupEncoder(t reflect.Type) (EncoderFunc, bool) {
+	encoder, ok := customEncoders.Load(t)
+	if !ok {
+		return getMarshalerEncoder(t)
+	}
+	return encoder.(EncoderFunc), true
+}
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	stringerType        = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// getMarshalerEncoder returns the encoder that uses `MarshalText()` of given type, or `String()` if the type doesn't implement `encoding.TextMarshaler`.
+// The methods that have a pointer receiver are also used. The types of `time` package (e.g. `time.Time` and `time.Month`) are out of this,
+// since they keep the built-in encoding. A pointer type and an interface type are also out of this; the pointer field is encoded by the encoder of the element type.
+func getMarshalerEncoder(t reflect.Type) (EncoderFunc, bool) {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface || t.PkgPath() == "time" {
+		return nil, false
+	}
+
+	switch {
+	case t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType):
+		return func(v reflect.Value) ([]string, error) {
+			text, err := addressable(v).Interface().(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			return []string{string(text)}, nil
+		}, true
+	case t.Implements(stringerType) || reflect.PtrTo(t).Implements(stringerType):
+		return func(v reflect.Value) ([]string, error) {
+			return []string{addressable(v).Interface().(fmt.Stringer).String()}, nil
+		}, true
+	}
+	return nil, false
+}
+
+// addressable returns the pointer of given value, so that the methods of both value and pointer receivers can be called.
+// If the value is not addressable, this copies that.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v.Addr()
+	}
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(v)
+	return ptr
+}
+
+// compileCustomEncoder compiles the encoder of the field whose type, or the element type of the pointer or the slice,
+// has the registered encoder, or implements `encoding.TextMarshaler` or `fmt.Stringer`.
+// If there is no such encoder for that, this returns nil.
+func compileCustomEncoder(paramName string, fieldType reflect.Type, opts *fieldOptions) func(qp url.Values, field reflect.Value) error {
+	if encoder, ok := lookupEncoder(fieldType); ok {
+		zeroChecker := getZeroChecker(fieldType)
+		return func(qp url.Values, field reflect.Value) error {
+			if opts.omitEmpty && isEmptyValue(field) {
+				return nil
+			}
+			if opts.omitZero && zeroChecker(field) {
+				return nil
+			}
+			return encodeCustomValue(qp, paramName, encoder, field)
+		}
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Ptr:
+		encoder, ok := lookupEncoder(fieldType.Elem())
+		if !ok {
+			return nil
+		}
+		return func(qp url.Values, field reflect.Value) error {
+			if field.IsNil() {
+				return nil
+			}
+			return encodeCustomValue(qp, paramName, encoder, field.Elem())
+		}
+	case reflect.Slice:
+		encoder, ok := lookupEncoder(fieldType.Elem())
+		if !ok {
+			return nil
+		}
+		// the values of the elements are collected into a string slice, and that is encoded according to the collection format
+		encodeSlice, blankParamName := compileSliceEncoder(paramName, func(v reflect.Value) string {
+			return v.String()
+		}, opts.collectionFormat)
+		return func(qp url.Values, field reflect.Value) error {
+			if field.Len() <= 0 && opts.keepEmpty && !opts.omitEmpty && !field.IsNil() {
+				qp.Set(blankParamName, "")
+				return nil
+			}
+			values := make([]string, 0, field.Len())
+			for j := 0; j < field.Len(); j++ {
+				vs, err := encoder(field.Index(j))
+				if err != nil {
+					return fmt.Errorf("parameter %s: %w", paramName, err)
+				}
+				values = append(values, vs...)
+			}
+			encodeSlice(qp, reflect.ValueOf(values))
+			return nil
+		}
+	}
+	return nil
+}
+
+// encodeCustomValue encodes given value by the registered encoder, and adds the values to the parameter.
+func encodeCustomValue(qp url.Values, paramName string, encoder EncoderFunc, v reflect.Value) error {
+	values, err := encoder(v)
+	if err != nil {
+		return fmt.Errorf("parameter %s: %w", paramName, err)
+	}
+	for _, value := range values {
+		qp.Add(paramName, value)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package taqc
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+type GeoPoint struct {
+	Lat float64
+	Lng float64
+}
+
+type TenantID string
+
+var errInvalidMoney = errors.New("invalid money")
+
+func init() {
+	RegisterEncoder(reflect.TypeOf(Money{}), func(v reflect.Value) ([]string, error) {
+		m := v.Interface().(Money)
+		if m.Currency == "" {
+			return nil, errInvalidMoney
+		}
+		return []string{fmt.Sprintf("%d%s", m.Amount, m.Currency)}, nil
+	})
+	RegisterEncoder(reflect.TypeOf(GeoPoint{}), func(v reflect.Value) ([]string, error) {
+		p := v.Interface().(GeoPoint)
+		if p == (GeoPoint{}) {
+			return nil, nil
+		}
+		return []string{fmt.Sprintf("%g", p.Lat), fmt.Sprintf("%g", p.Lng)}, nil
+	})
+	RegisterEncoder(reflect.TypeOf(TenantID("")), func(v reflect.Value) ([]string, error) {
+		return []string{"tenant-" + v.String()}, nil
+	})
+}
+
+func TestConvertToQueryParams_WithRegisteredEncoder(t *testing.T) {
+	type Query struct {
+		Price     Money      `taqc:"price"`
+		MaxPrice  *Money     `taqc:"max_price"`
+		NilPrice  *Money     `taqc:"nil_price"`
+		Prices    []Money    `taqc:"prices, collectionFormat=csv"`
+		Point     GeoPoint   `taqc:"point"`
+		Zero      GeoPoint   `taqc:"zero"`
+		Points    []GeoPoint `taqc:"points"`
+		Empty     []Money    `taqc:"empty, keepEmpty"`
+		Tenant    TenantID   `taqc:"tenant"`
+		NotTenant string     `taqc:"not_tenant"`
+	}
+
+	qp, err := ConvertToQueryParams(&Query{
+		Price:     Money{Amount: 100, Currency: "JPY"},
+		MaxPrice:  &Money{Amount: 200, Currency: "JPY"},
+		Prices:    []Money{{Amount: 1, Currency: "USD"}, {Amount: 2, Currency: "EUR"}},
+		Point:     GeoPoint{Lat: 35.5, Lng: 139.5},
+		Points:    []GeoPoint{{Lat: 1, Lng: 2}, {Lat: 3, Lng: 4}},
+		Empty:     []Money{},
+		Tenant:    "foo",
+		NotTenant: "bar",
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"price":      []string{"100JPY"},
+		"max_price":  []string{"200JPY"},
+		"prices":     []string{"1USD,2EUR"},
+		"point":      []string{"35.5", "139.5"},
+		"points":     []string{"1", "2", "3", "4"},
+		"empty":      []string{""},
+		"tenant":     []string{"tenant-foo"},
+		"not_tenant": []string{"bar"},
+	}, qp)
+}
+
+func TestConvertToQueryParams_ShouldPropagateErrorOfRegisteredEncoder(t *testing.T) {
+	type Query struct {
+		Price Money `taqc:"price"`
+	}
+	_, err := ConvertToQueryParams(&Query{})
+	assert.ErrorIs(t, err, errInvalidMoney)
+	assert.Contains(t, err.Error(), "price")
+
+	type SliceQuery struct {
+		Prices []Money `taqc:"prices"`
+	}
+	_, err = ConvertToQueryParams(&SliceQuery{Prices: []Money{{}}})
+	assert.ErrorIs(t, err, errInvalidMoney)
+}
+
+func TestRegisterEncoder_ShouldDiscardCompiledPlans(t *testing.T) {
+	type Code int64
+	type Query struct {
+		Code Code `taqc:"code"`
+	}
+
+	qp, err := ConvertToQueryParams(&Query{Code: 42})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{"code": []string{"42"}}, qp)
+
+	RegisterEncoder(reflect.TypeOf(Code(0)), func(v reflect.Value) ([]string, error) {
+		return []string{fmt.Sprintf("C%03d", v.Int())}, nil
+	})
+	qp, err = ConvertToQueryParams(&Query{Code: 42})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{"code": []string{"C042"}}, qp)
+}
+
+func TestRegisterEncoder_ShouldDiscardCompiledPlansWhileConverting(t *testing.T) {
+	type Code int64
+	type Query struct {
+		Code Code `taqc:"code"`
+	}
+
+	// the plans that are compiled concurrently with the registration must not outlive that
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := ConvertToQueryParams(&Query{Code: 42})
+			assert.NoError(t, err)
+		}()
+	}
+	RegisterEncoder(reflect.TypeOf(Code(0)), func(v reflect.Value) ([]string, error) {
+		return []string{fmt.Sprintf("C%03d", v.Int())}, nil
+	})
+	wg.Wait()
+
+	qp, err := ConvertToQueryParams(&Query{Code: 42})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{"code": []string{"C042"}}, qp)
+}
+
+type Color int
+
+func (c Color) String() string {
+	return [...]string{"red", "green", "blue"}[c]
+}
+
+type Status int
+
+const (
+	StatusActive Status = iota + 1
+	StatusClosed
+)
+
+var errInvalidStatus = errors.New("invalid status")
+
+func (s Status) MarshalText() ([]byte, error) {
+	switch s {
+	case StatusActive:
+		return []byte("active"), nil
+	case StatusClosed:
+		return []byte("closed"), nil
+	}
+	return nil, errInvalidStatus
+}
+
+func (s *Status) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "active":
+		*s = StatusActive
+	case "closed":
+		*s = StatusClosed
+	default:
+		return errInvalidStatus
+	}
+	return nil
+}
+
+// String must be ignored since MarshalText is preferred.
+func (s Status) String() string {
+	return "status"
+}
+
+type Ref struct {
+	Kind string
+	ID   int64
+}
+
+func (r *Ref) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%s:%d", r.Kind, r.ID)), nil
+}
+
+func TestConvertToQueryParams_WithMarshaler(t *testing.T) {
+	type Query struct {
+		Color      Color         `taqc:"color"`
+		Colors     []Color       `taqc:"colors, collectionFormat=csv"`
+		Status     Status        `taqc:"status"`
+		StatusPtr  *Status       `taqc:"status_ptr"`
+		NilStatus  *Status       `taqc:"nil_status"`
+		Statuses   []Status      `taqc:"statuses"`
+		OmitStatus Status        `taqc:"omit_status, omitempty"`
+		Ref        Ref           `taqc:"ref"`
+		Refs       []Ref         `taqc:"refs"`
+		ZeroRef    Ref           `taqc:"zero_ref, omitzero"`
+		Time       time.Time     `taqc:"time"`
+		Duration   time.Duration `taqc:"duration"`
+	}
+
+	now := time.Now()
+	qp, err := ConvertToQueryParams(&Query{
+		Color:     2,
+		Colors:    []Color{0, 1},
+		Status:    StatusActive,
+		StatusPtr: func() *Status { s := StatusClosed; return &s }(),
+		Statuses:  []Status{StatusActive, StatusClosed},
+		Ref:       Ref{Kind: "user", ID: 1},
+		Refs:      []Ref{{Kind: "org", ID: 2}},
+		Time:      now,
+		Duration:  time.Second,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"color":      []string{"blue"},
+		"colors":     []string{"red,green"},
+		"status":     []string{"active"},
+		"status_ptr": []string{"closed"},
+		"statuses":   []string{"active", "closed"},
+		"ref":        []string{"user:1"},
+		"refs":       []string{"org:2"},
+		"time":       []string{fmt.Sprintf("%d", now.Unix())},
+		"duration":   []string{"1000000000"},
+	}, qp)
+}
+
+func TestConvertToQueryParams_ShouldPropagateErrorOfMarshaler(t *testing.T) {
+	type Query struct {
+		Status Status `taqc:"status"`
+	}
+	_, err := ConvertToQueryParams(&Query{Status: 42})
+	assert.ErrorIs(t, err, errInvalidStatus)
+	assert.Contains(t, err.Error(), "status")
+
+	type SliceQuery struct {
+		Statuses []Status `taqc:"statuses"`
+	}
+	_, err = ConvertToQueryParams(&SliceQuery{Statuses: []Status{StatusActive, 42}})
+	assert.ErrorIs(t, err, errInvalidStatus)
+}
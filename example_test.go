@@ -1,6 +1,9 @@
 package taqc
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+)
 
 func ExampleConvertToQueryParams() {
 	type Query struct {
@@ -31,3 +34,29 @@ func ExampleConvertToQueryParams() {
 	// Output:
 	// buz=123&foo=string_value&foobar=1&qux=123.456000&qux=234.567000
 }
+
+func ExampleUnmarshalQueryParams() {
+	type Query struct {
+		Foo    string    `taqc:"foo"`
+		Bar    *string   `taqc:"bar"`
+		Buz    int64     `taqc:"buz"`
+		Qux    []float64 `taqc:"qux"`
+		FooBar bool      `taqc:"foobar"`
+	}
+
+	queryParams, err := url.ParseQuery("buz=123&foo=string_value&foobar=1&qux=123.456000&qux=234.567000")
+	if err != nil {
+		panic(err)
+	}
+
+	var q Query
+	err = UnmarshalQueryParams(queryParams, &q)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%s %v %d %v %v\n", q.Foo, q.Bar, q.Buz, q.Qux, q.FooBar)
+
+	// Output:
+	// string_value <nil> 123 [123.456 234.567] true
+}
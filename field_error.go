@@ -0,0 +1,125 @@
+package taqc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/moznion/taqc/internal"
+)
+
+// FieldError is the error of a field of the structure.
+// That wraps the cause, so `errors.Is()` works with the sentinel errors (e.g. `ErrUnsupportedFieldType`) as well.
+type FieldError struct {
+	// Path is the path of the field from the given structure; e.g. `Filter.Status`, or `Items[1].SKU` for the element of a slice.
+	Path string
+	// ParamName is the query parameter name of the field. This is empty when the tag doesn't have that.
+	ParamName string
+	// Type is the type of the field.
+	Type reflect.Type
+	// Err is the cause of the error.
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %s (parameter %q, type %s): %s", e.Path, e.ParamName, e.Type, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors is the errors of the fields, which is returned when `CollectAllErrors()` option is given.
+// `errors.Is()` and `errors.As()` work with any of the errors.
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldError := range e {
+		messages[i] = fieldError.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e FieldErrors) Is(target error) bool {
+	for _, fieldError := range e {
+		if errors.Is(fieldError, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e FieldErrors) As(target interface{}) bool {
+	for _, fieldError := range e {
+		if errors.As(fieldError, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// newFieldError returns the error of given field. The placeholders of the indices in the path and the parameter name are left as they are.
+func newFieldError(structField *structField, err error) *FieldError {
+	return &FieldError{
+		Path:      structField.path,
+		ParamName: structField.paramName,
+		Type:      structField.typ,
+		Err:       err,
+	}
+}
+
+// appendFieldErrors appends given error to the field errors. The field errors in that are flattened, and the other error is regarded as the one of given field.
+func appendFieldErrors(errs FieldErrors, structField *structField, err error) FieldErrors {
+	var fieldErrors FieldErrors
+	if errors.As(err, &fieldErrors) {
+		return append(errs, fieldErrors...)
+	}
+	var fieldError *FieldError
+	if errors.As(err, &fieldError) {
+		return append(errs, fieldError)
+	}
+	return append(errs, newFieldError(structField, err))
+}
+
+// fillFieldErrorIndex replaces the placeholders of the index of the innermost slice in the path and the parameter name of the field errors with given index.
+func fillFieldErrorIndex(errs FieldErrors, index int) FieldErrors {
+	filled := make(FieldErrors, len(errs))
+	for i, fieldError := range errs {
+		filled[i] = &FieldError{
+			Path:      internal.FillIndex(fieldError.Path, index),
+			ParamName: internal.FillIndex(fieldError.ParamName, index),
+			Type:      fieldError.Type,
+			Err:       fieldError.Err,
+		}
+	}
+	return filled
+}
+
+// firstError returns the first error of the field errors, or given error as it is when that is not the field errors.
+func firstError(err error) error {
+	if fieldErrors, ok := err.(FieldErrors); ok && len(fieldErrors) > 0 {
+		return fieldErrors[0]
+	}
+	return err
+}
+
+// maskFieldErrorIndices replaces the placeholders of the indices in the paths and the parameter names of the field errors with `*`;
+// the errors of a structure type are not specific to any element of the slices.
+func maskFieldErrorIndices(err error) error {
+	fieldErrors, ok := err.(FieldErrors)
+	if !ok {
+		return err
+	}
+	masked := make(FieldErrors, len(fieldErrors))
+	for i, fieldError := range fieldErrors {
+		masked[i] = &FieldError{
+			Path:      strings.ReplaceAll(fieldError.Path, internal.IndexPlaceholder, "*"),
+			ParamName: strings.ReplaceAll(fieldError.ParamName, internal.IndexPlaceholder, "*"),
+			Type:      fieldError.Type,
+			Err:       fieldError.Err,
+		}
+	}
+	return masked
+}
@@ -0,0 +1,162 @@
+package taqc
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToQueryParams_ShouldReturnFieldError(t *testing.T) {
+	type Query struct {
+		Foo string              `taqc:"foo"`
+		Bar []unsupportedStruct `taqc:"bar"`
+	}
+
+	_, err := ConvertToQueryParams(&Query{Bar: []unsupportedStruct{{}}})
+	var fieldError *FieldError
+	assert.True(t, errors.As(err, &fieldError))
+	assert.Equal(t, "Bar", fieldError.Path)
+	assert.Equal(t, "bar", fieldError.ParamName)
+	assert.Equal(t, reflect.TypeOf([]unsupportedStruct{}), fieldError.Type)
+	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+	assert.Equal(t, `field Bar (parameter "bar", type []taqc.unsupportedStruct): taqc.unsupportedStruct is unsupported: unsupported filed type has come`, err.Error())
+}
+
+func TestConvertToQueryParams_ShouldReturnFieldErrorOfNestedField(t *testing.T) {
+	type Item struct {
+		SKU  string    `taqc:"sku"`
+		Time time.Time `taqc:"time, unixTimeUnit=fortnight"`
+	}
+	type Filter struct {
+		Status *unsupportedStruct `taqc:"status"`
+	}
+	type Query struct {
+		Filter Filter  `taqc:"filter, inline, nestStyle=dot"`
+		Items  []*Item `taqc:"items, inline"`
+	}
+
+	_, err := ConvertToQueryParams(&Query{Filter: Filter{Status: &unsupportedStruct{}}})
+	var fieldError *FieldError
+	assert.True(t, errors.As(err, &fieldError))
+	assert.Equal(t, "Filter.Status", fieldError.Path)
+	assert.Equal(t, "filter.status", fieldError.ParamName)
+	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+
+	_, err = ConvertToQueryParams(&Query{Items: []*Item{nil, {SKU: "A"}}})
+	assert.True(t, errors.As(err, &fieldError))
+	assert.Equal(t, "Items[1].Time", fieldError.Path)
+	assert.Equal(t, "items[1].time", fieldError.ParamName)
+	assert.Equal(t, reflect.TypeOf(time.Time{}), fieldError.Type)
+	assert.ErrorIs(t, err, ErrUnsupportedUnixTimeUnit)
+}
+
+func TestConvertToQueryParams_WithCollectAllErrors(t *testing.T) {
+	type Item struct {
+		Bad unsupportedStruct `taqc:"bad"`
+	}
+	type Query struct {
+		Foo   string            `taqc:"foo"`
+		Bar   unsupportedStruct `taqc:"bar"`
+		Time  time.Time         `taqc:"time, unixTimeUnit=fortnight"`
+		Items []Item            `taqc:"items, inline"`
+		Buz   float64           `taqc:"buz, floatFormat=round"`
+	}
+
+	_, err := ConvertToQueryParams(&Query{Items: []Item{{}, {}}}, CollectAllErrors())
+	var fieldErrors FieldErrors
+	assert.True(t, errors.As(err, &fieldErrors))
+	paths := make([]string, len(fieldErrors))
+	for i, fieldError := range fieldErrors {
+		paths[i] = fieldError.Path
+	}
+	assert.Equal(t, []string{"Bar", "Time", "Items[0].Bad", "Items[1].Bad", "Buz"}, paths)
+	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+	assert.ErrorIs(t, err, ErrUnsupportedUnixTimeUnit)
+	assert.ErrorIs(t, err, ErrUnsupportedFloatFormat)
+	assert.NotErrorIs(t, err, ErrMalformedTag)
+
+	var fieldError *FieldError
+	assert.True(t, errors.As(err, &fieldError))
+	assert.Equal(t, "Bar", fieldError.Path)
+
+	// without the option, it stops at the first one
+	_, err = ConvertToQueryParams(&Query{})
+	assert.True(t, errors.As(err, &fieldError))
+	assert.False(t, errors.As(err, &fieldErrors))
+	assert.Equal(t, "Bar", fieldError.Path)
+
+	qp, err := ConvertToQueryParams(&struct {
+		Foo string `taqc:"foo"`
+	}{Foo: "foo"}, CollectAllErrors())
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{"foo": []string{"foo"}}, qp)
+}
+
+func TestConvertToQueryParams_ShouldCollectAllErrorsOfTags(t *testing.T) {
+	type Item struct {
+		SKU string `taqc:"sku, unknown"`
+	}
+	type Query struct {
+		Foo   string `taqc:""`
+		Bar   string `taqc:"bar, omitempty, omitempty"`
+		Items []Item `taqc:"items, inline"`
+	}
+
+	_, err := ConvertToQueryParams(&Query{}, CollectAllErrors())
+	var fieldErrors FieldErrors
+	assert.True(t, errors.As(err, &fieldErrors))
+	assert.Len(t, fieldErrors, 3)
+	assert.ErrorIs(t, fieldErrors[0], ErrQueryParameterNameIsEmpty)
+	assert.Equal(t, "Foo", fieldErrors[0].Path)
+	assert.ErrorIs(t, fieldErrors[1], ErrDuplicatedTagOption)
+	assert.Equal(t, "Bar", fieldErrors[1].Path)
+	assert.ErrorIs(t, fieldErrors[2], ErrUnknownTagOption)
+	assert.Equal(t, "Items[*].SKU", fieldErrors[2].Path)
+
+	_, err = ConvertToQueryParams(&Query{})
+	var fieldError *FieldError
+	assert.True(t, errors.As(err, &fieldError))
+	assert.Equal(t, "Foo", fieldError.Path)
+
+	err = UnmarshalQueryParams(url.Values{}, &Query{})
+	assert.True(t, errors.As(err, &fieldError))
+	assert.Equal(t, "Foo", fieldError.Path)
+}
+
+func TestConvertToQueryParams_ShouldRaiseErrorWhenNotPointerOfStruct(t *testing.T) {
+	type Query struct {
+		Foo string `taqc:"foo"`
+	}
+
+	_, err := ConvertToQueryParams(Query{Foo: "foo"})
+	assert.ErrorIs(t, err, ErrNonPointerValueGiven)
+
+	i := 1
+	_, err = ConvertToQueryParams(&i)
+	assert.ErrorIs(t, err, ErrNonPointerValueGiven)
+
+	_, err = ConvertToQueryParams((*Query)(nil))
+	assert.ErrorIs(t, err, ErrNilValueGiven)
+}
+
+func TestConvertToQueryParams_ShouldRaiseErrorWithUnexportedTaggedField(t *testing.T) {
+	type Query struct {
+		Foo string `taqc:"foo"`
+		bar string `taqc:"bar"`
+	}
+
+	_, err := ConvertToQueryParams(&Query{Foo: "foo", bar: "bar"})
+	assert.ErrorIs(t, err, ErrUnexportedField)
+	var fieldError *FieldError
+	assert.True(t, errors.As(err, &fieldError))
+	assert.Equal(t, "bar", fieldError.Path)
+	assert.Equal(t, "bar", fieldError.ParamName)
+	assert.EqualError(t, fieldError, `field bar (parameter "bar", type string): unexported field cannot have a tag`)
+
+	err = UnmarshalQueryParams(url.Values{"bar": []string{"bar"}}, &Query{})
+	assert.ErrorIs(t, err, ErrUnexportedField)
+}
@@ -0,0 +1,167 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moznion/taqc/gen/internal"
+	"github.com/pmezard/go-difflib/difflib"
+	"golang.org/x/tools/go/packages"
+)
+
+// generatedHeaderPrefix is the prefix of the header comment of the code that taqc generates.
+const generatedHeaderPrefix = "// Code generated by taqc"
+
+// Drift is the difference between the code that is generated now and the file that has been generated before.
+type Drift struct {
+	// Path is the path of the file that drifts.
+	Path string
+	// Orphan is true when the file has been generated by taqc, but the type of that doesn't exist anymore.
+	Orphan bool
+	// Diff is the unified diff from the file to the code that is generated now; an orphan is diffed against the empty content.
+	Diff string
+}
+
+// Check regenerates the code in memory in the same manner as GenerateFiles, and compares that with the files that have been generated.
+// This returns the drifts; no drift means the files are up to date.
+// output is the path of the file to compare with instead of File.Path, which is available only for a single package; empty means File.Path.
+func Check(cfg Config, output string) ([]*Drift, error) {
+	pkgs, err := internal.ParsePackages(cfg.patterns(), cfg.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse a package: %w", err)
+	}
+
+	files, err := generatePackageFiles(pkgs, cfg)
+	if err != nil && !errors.Is(err, ErrNoTypeName) {
+		// no type to generate is fine; the generated files of those are orphans if there are
+		return nil, err
+	}
+	if output != "" {
+		if len(files) > 1 {
+			return nil, fmt.Errorf("output cannot be given for %d packages: %w", len(files), ErrMultiplePackages)
+		}
+		if len(files) == 1 {
+			files[0].Path = output
+		}
+	}
+
+	drifts := make([]*Drift, 0)
+	expected := make(map[string]bool, len(files))
+	for _, file := range files {
+		expected[absPath(file.Path)] = true
+
+		current, err := ioutil.ReadFile(file.Path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read the generated file: %w", err)
+		}
+		if string(current) == string(file.Code) {
+			continue
+		}
+		diff, err := unifiedDiff(file.Path, string(current), string(file.Code))
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, &Drift{Path: file.Path, Diff: diff})
+	}
+
+	for _, pkg := range pkgs {
+		for _, path := range findOrphans(pkg) {
+			if expected[absPath(path)] {
+				continue
+			}
+			current, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the generated file: %w", err)
+			}
+			diff, err := unifiedDiff(path, string(current), "")
+			if err != nil {
+				return nil, err
+			}
+			drifts = append(drifts, &Drift{Path: path, Orphan: true, Diff: diff})
+		}
+	}
+
+	return drifts, nil
+}
+
+// findOrphans returns the paths of the files in the package that have been generated by taqc for the types that don't exist anymore.
+func findOrphans(pkg *packages.Package) []string {
+	orphans := make([]string, 0)
+	for i, file := range pkg.Syntax {
+		if i >= len(pkg.CompiledGoFiles) || !isGeneratedByTaqc(file) {
+			continue
+		}
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) <= 0 {
+				continue
+			}
+			typeName := receiverTypeName(funcDecl.Recv.List[0].Type)
+			if typeName != "" && pkg.Types.Scope().Lookup(typeName) == nil {
+				orphans = append(orphans, pkg.CompiledGoFiles[i])
+				break
+			}
+		}
+	}
+	return orphans
+}
+
+func isGeneratedByTaqc(file *ast.File) bool {
+	if len(file.Comments) <= 0 || file.Comments[0].Pos() > file.Package {
+		return false
+	}
+	header := file.Comments[0].List[0].Text
+	return strings.HasPrefix(header, generatedHeaderPrefix) && strings.HasSuffix(header, "DO NOT EDIT.")
+}
+
+func receiverTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(e.X)
+	case *ast.Ident:
+		return e.Name
+	default:
+		return ""
+	}
+}
+
+func unifiedDiff(path string, current string, generated string) (string, error) {
+	name := path
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, absPath(path)); err == nil && !strings.HasPrefix(rel, "..") {
+			name = rel
+		}
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(current),
+		B:        splitLines(generated),
+		FromFile: name,
+		ToFile:   name + " (generated)",
+		Context:  3,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to take the diff of %s: %w", path, err)
+	}
+	return diff, nil
+}
+
+// splitLines splits the content into the lines; the empty content has no line, unlike difflib.SplitLines.
+func splitLines(content string) []string {
+	if content == "" {
+		return []string{}
+	}
+	return difflib.SplitLines(content)
+}
+
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return abs
+}
@@ -0,0 +1,112 @@
+package gen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeCheckPackage writes the package of given source into a temporary module, and changes the working directory to that module.
+// This returns the pattern of the package.
+func writeCheckPackage(t *testing.T, source string) string {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module check\n\ngo 1.17\n")
+	writeFile(t, filepath.Join(dir, "check.go"), source)
+
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	err = os.Chdir(dir)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+	})
+	return "."
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	assert.NoError(t, err)
+}
+
+func TestCheck(t *testing.T) {
+	pattern := writeCheckPackage(t, `package check
+
+//taqc:generate
+type Query struct {
+	Name string `+"`taqc:\"name\"`"+`
+}
+`)
+	cfg := Config{
+		Patterns: []string{pattern},
+		Args:     []string{"--decoder"},
+		Decoder:  true,
+	}
+
+	// not generated yet
+	drifts, err := Check(cfg, "")
+	assert.NoError(t, err)
+	assert.Len(t, drifts, 1)
+	assert.False(t, drifts[0].Orphan)
+	assert.Contains(t, drifts[0].Diff, "+// Code generated by taqc --decoder; DO NOT EDIT.")
+
+	files, err := GenerateFiles(cfg)
+	assert.NoError(t, err)
+	writeFile(t, files[0].Path, string(files[0].Code))
+
+	drifts, err = Check(cfg, "")
+	assert.NoError(t, err)
+	assert.Empty(t, drifts)
+
+	// a field is renamed
+	writeFile(t, filepath.Join(pattern, "check.go"), `package check
+
+//taqc:generate
+type Query struct {
+	Keyword string `+"`taqc:\"q\"`"+`
+}
+`)
+	drifts, err = Check(cfg, "")
+	assert.NoError(t, err)
+	assert.Len(t, drifts, 1)
+	assert.Equal(t, files[0].Path, drifts[0].Path)
+	assert.Contains(t, drifts[0].Diff, `-	qp.Set("name", v.Name)`)
+	assert.Contains(t, drifts[0].Diff, `+	qp.Set("q", v.Keyword)`)
+
+	// the type is deleted
+	writeFile(t, filepath.Join(pattern, "check.go"), `package check
+`)
+	drifts, err = Check(cfg, "")
+	assert.NoError(t, err)
+	assert.Len(t, drifts, 1)
+	assert.True(t, drifts[0].Orphan)
+	assert.Equal(t, "query_gen.go", filepath.Base(drifts[0].Path))
+}
+
+func TestCheck_Output(t *testing.T) {
+	pattern := writeCheckPackage(t, `package check
+
+type Query struct {
+	Name string `+"`taqc:\"name\"`"+`
+}
+`)
+	cfg := Config{
+		TypeNames: []string{"Query"},
+		Patterns:  []string{pattern},
+	}
+	code, err := Generate(cfg)
+	assert.NoError(t, err)
+	output := filepath.Join(pattern, "custom.go")
+	writeFile(t, output, string(code))
+
+	drifts, err := Check(cfg, output)
+	assert.NoError(t, err)
+	assert.Empty(t, drifts)
+
+	drifts, err = Check(cfg, "")
+	assert.NoError(t, err)
+	assert.Len(t, drifts, 1)
+	assert.Equal(t, "query_gen.go", filepath.Base(drifts[0].Path))
+}
@@ -0,0 +1,106 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/moznion/taqc/gen/internal"
+)
+
+// Diagnostic is a problem of a field that prevents the generation, with the position of that in the source code.
+// The error message is in the form of `file:line:col: message`, which the editors understand.
+type Diagnostic struct {
+	// Pos is the position of the field in the file set that the package is loaded with.
+	Pos      token.Pos `json:"-"`
+	Filename string    `json:"filename"`
+	Line     int       `json:"line"`
+	Column   int       `json:"column"`
+	// TypeName is the name of the type to generate, and FieldName is the name of the field that has the problem.
+	// The field can be the one of a nested struct of the type.
+	TypeName  string `json:"type"`
+	FieldName string `json:"field"`
+	Message   string `json:"message"`
+	// Err is the cause of the problem; `errors.Is()` works with the sentinel errors (e.g. `taqc.ErrUnsupportedFieldType`) through this.
+	Err error `json:"-"`
+}
+
+func (d *Diagnostic) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s.%s: %s", d.Filename, d.Line, d.Column, d.TypeName, d.FieldName, d.Message)
+}
+
+func (d *Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// Diagnostics is the problems of all the fields, which Generate and the family return as an error.
+// `errors.Is()` and `errors.As()` work with any of the problems.
+type Diagnostics []*Diagnostic
+
+func (d Diagnostics) Error() string {
+	messages := make([]string, len(d))
+	for i, diagnostic := range d {
+		messages[i] = diagnostic.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (d Diagnostics) Is(target error) bool {
+	for _, diagnostic := range d {
+		if errors.Is(diagnostic, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d Diagnostics) As(target interface{}) bool {
+	for _, diagnostic := range d {
+		if errors.As(diagnostic, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Diagnose reports the problems of the fields of given struct type in the package, in the same manner as Generate does.
+// Config.TypeNames and Config.Patterns are ignored, and fset is the file set that the package has been type-checked with.
+// This returns an error when the type isn't a struct that has the fields to generate the methods for.
+func Diagnose(pkg *types.Package, fset *token.FileSet, typeName string, cfg Config) (Diagnostics, error) {
+	_, diagnostics, err := collectFields(pkg, fset, typeName, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return diagnostics, nil
+}
+
+// toDiagnostics converts the errors of the fields of given type to the diagnostics, in the order of the positions.
+func toDiagnostics(fieldErrors internal.FieldErrors, typeName string, fset *token.FileSet) Diagnostics {
+	diagnostics := make(Diagnostics, len(fieldErrors))
+	for i, fieldError := range fieldErrors {
+		position := fset.Position(fieldError.Pos)
+		diagnostics[i] = &Diagnostic{
+			Pos:       fieldError.Pos,
+			Filename:  position.Filename,
+			Line:      position.Line,
+			Column:    position.Column,
+			TypeName:  typeName,
+			FieldName: fieldError.FieldName,
+			Message:   fieldError.Err.Error(),
+			Err:       fieldError.Err,
+		}
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Filename != diagnostics[j].Filename {
+			return diagnostics[i].Filename < diagnostics[j].Filename
+		}
+		if diagnostics[i].Line != diagnostics[j].Line {
+			return diagnostics[i].Line < diagnostics[j].Line
+		}
+		return diagnostics[i].Column < diagnostics[j].Column
+	})
+	return diagnostics
+}
@@ -0,0 +1,75 @@
+package gen
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/moznion/taqc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate_Diagnostics(t *testing.T) {
+	_, err := Generate(Config{
+		TypeNames: []string{"Broken", "AlsoBroken"},
+		Patterns:  []string{"./testdata/diagnostics"},
+		Decoder:   true,
+	})
+
+	var diagnostics Diagnostics
+	assert.True(t, errors.As(err, &diagnostics))
+	assert.True(t, errors.Is(err, taqc.ErrQueryParameterNameIsEmpty))
+	assert.True(t, errors.Is(err, taqc.ErrUnsupportedUnixTimeUnit))
+	assert.True(t, errors.Is(err, taqc.ErrUnsupportedFieldType))
+	assert.True(t, errors.Is(err, taqc.ErrAmbiguousQueryParameter))
+
+	type position struct {
+		file      string
+		line      int
+		column    int
+		typeName  string
+		fieldName string
+	}
+	positions := make([]position, len(diagnostics))
+	for i, diagnostic := range diagnostics {
+		positions[i] = position{filepath.Base(diagnostic.Filename), diagnostic.Line, diagnostic.Column, diagnostic.TypeName, diagnostic.FieldName}
+	}
+	assert.Equal(t, []position{
+		{"diagnostics.go", 6, 2, "Broken", "Empty"},
+		{"diagnostics.go", 7, 2, "Broken", "Since"},
+		{"diagnostics.go", 8, 2, "Broken", "Value"},
+		{"diagnostics.go", 10, 2, "Broken", "Duplicate"},
+		{"diagnostics.go", 13, 3, "Broken", "Value"},
+		{"diagnostics.go", 18, 2, "AlsoBroken", "Value"},
+	}, positions)
+
+	assert.Regexp(t, `^/.+/testdata/diagnostics/diagnostics\.go:7:2: Broken\.Since: minute is unsupported: `, diagnostics[1].Error())
+
+	diagnosticsJSON, err := json.Marshal(diagnostics[5])
+	assert.NoError(t, err)
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(diagnosticsJSON, &decoded))
+	assert.Equal(t, float64(18), decoded["line"])
+	assert.Equal(t, "AlsoBroken", decoded["type"])
+	assert.Equal(t, "Value", decoded["field"])
+	assert.Equal(t, "complex64 is unsupported: unsupported filed type has come", decoded["message"])
+}
+
+func TestGenerateFiles_DiagnosticsAcrossPackages(t *testing.T) {
+	_, err := GenerateFiles(Config{
+		TypeNames: []string{"Broken", "Query"},
+		Patterns:  []string{"./testdata/diagnostics", "./testdata/example"},
+	})
+	var diagnostics Diagnostics
+	assert.True(t, errors.As(err, &diagnostics))
+	assert.Len(t, diagnostics, 5)
+
+	_, err = GenerateFiles(Config{
+		TypeNames: []string{"Broken", "Unsupported"},
+		Patterns:  []string{"./testdata/diagnostics", "./testdata/example"},
+	})
+	assert.True(t, errors.As(err, &diagnostics))
+	assert.Len(t, diagnostics, 6)
+	assert.Equal(t, "Unsupported", diagnostics[5].TypeName)
+}
@@ -0,0 +1,1495 @@
+// Package gen generates the code that converts the structures to the query parameters, and vice versa, without reflection.
+// This is the implementation of `taqc` command; the command is a thin wrapper of this package.
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iancoleman/strcase"
+	g "github.com/moznion/gowrtr/generator"
+	"github.com/moznion/taqc"
+	"github.com/moznion/taqc/gen/internal"
+	taqcinternal "github.com/moznion/taqc/internal"
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	ErrNoTypeName       = errors.New("no type name has given")
+	ErrMultiplePackages = errors.New("multiple packages have the types to generate")
+	ErrNoSuchType       = errors.New("there is no such type in the packages")
+	ErrNoPackageFound   = internal.ErrNoPackageFound
+	ErrFallibleEncoding = errors.New("the encoding can fail, so that needs Fallible to generate `ToQueryParameters() (url.Values, error)`")
+)
+
+// Config is the configuration of the code generation.
+type Config struct {
+	// TypeNames is the names of the struct types to generate the methods for.
+	// When this is empty, the structs that have `//taqc:generate` comment are the ones to generate the methods for.
+	TypeNames []string
+	// Patterns is the patterns of the packages that have the types, e.g. a directory, files, or `./...`. The default is the current directory.
+	Patterns []string
+	// Tags is the build tags to satisfy on loading the packages.
+	Tags []string
+	// Decoder makes it generate `FromQueryParameters(url.Values) error` method as well.
+	Decoder bool
+	// Fallible makes it generate `ToQueryParameters() (url.Values, error)` method instead of `ToQueryParameters() url.Values`.
+	// This is necessary for the fields that can fail to be encoded, i.e. the ones that are encoded by Encoders or `MarshalText()`.
+	Fallible bool
+	// Encoders is the mapping from a type to the name of the function that encodes a value of that type; e.g. `Money` to `encodeMoney`.
+	Encoders map[string]string
+	// TimeFormatters and TimeParsers are the mappings from the name that `timeFormatter` option refers to,
+	// to the name of the function that formats `time.Time` and the one that parses that respectively.
+	TimeFormatters map[string]string
+	TimeParsers    map[string]string
+	// Args is the arguments of the command, which are noted in the header comment of the generated code.
+	Args []string
+}
+
+// File is the generated code for a package.
+type File struct {
+	// Path is the path of the file to write the code into; that is `<dir>/<type>_gen.go`,
+	// where the directory is of the package and the type is the first one of TypeNames in snake case.
+	Path string
+	// PackagePath is the import path of the package.
+	PackagePath string
+	// TypeNames is the names of the types that the code has the methods for.
+	TypeNames []string
+	Code      []byte
+}
+
+// patterns returns the package patterns, or the current directory when there is no pattern.
+func (cfg *Config) patterns() []string {
+	if len(cfg.Patterns) <= 0 {
+		return []string{"."}
+	}
+	return cfg.Patterns
+}
+
+// Generate generates the code of the methods of the types according to given configuration, and returns that.
+// The generated code has `ToQueryParameters()` method, and `FromQueryParameters(url.Values) error` method if Config.Decoder is true, for each type.
+// The types must be in a single package; use GenerateFiles for multiple packages.
+func Generate(cfg Config) ([]byte, error) {
+	files, err := GenerateFiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) != 1 {
+		packagePaths := make([]string, len(files))
+		for i, file := range files {
+			packagePaths[i] = file.PackagePath
+		}
+		return nil, fmt.Errorf("%s: %w", strings.Join(packagePaths, ", "), ErrMultiplePackages)
+	}
+	return files[0].Code, nil
+}
+
+// GenerateTo generates the code in the same manner as Generate, and writes that to w.
+func GenerateTo(w io.Writer, cfg Config) error {
+	code, err := Generate(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, bytes.NewReader(code))
+	return err
+}
+
+// GenerateFiles generates the code for each package that matches Config.Patterns, in the same manner as Generate.
+// The packages that have no type to generate are skipped.
+func GenerateFiles(cfg Config) ([]*File, error) {
+	pkgs, err := internal.ParsePackages(cfg.patterns(), cfg.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse a package: %w", err)
+	}
+	return generatePackageFiles(pkgs, cfg)
+}
+
+// generatePackageFiles generates the code for each package of pkgs.
+func generatePackageFiles(pkgs []*packages.Package, cfg Config) ([]*File, error) {
+	found := make(map[string]bool, len(cfg.TypeNames))
+	files := make([]*File, 0, len(pkgs))
+	var diagnostics Diagnostics
+	for _, pkg := range pkgs {
+		var typeNames []string
+		if len(cfg.TypeNames) > 0 {
+			for _, typeName := range cfg.TypeNames {
+				// a single package takes all the types, so that it reports the type that isn't there in detail
+				if len(pkgs) == 1 || pkg.Types.Scope().Lookup(typeName) != nil {
+					typeNames = append(typeNames, typeName)
+					found[typeName] = true
+				}
+			}
+		} else {
+			typeNames = internal.CollectMarkedTypeNames(pkg)
+		}
+		if len(typeNames) <= 0 {
+			continue
+		}
+
+		code, err := generateFile(pkg, typeNames, cfg)
+		if err != nil {
+			var packageDiagnostics Diagnostics
+			if errors.As(err, &packageDiagnostics) {
+				// go through the rest of the packages to report all the problems
+				diagnostics = append(diagnostics, packageDiagnostics...)
+				continue
+			}
+			return nil, fmt.Errorf("%s: %w", pkg.PkgPath, err)
+		}
+		files = append(files, &File{
+			Path:        filepath.Join(packageDir(pkg), strcase.ToSnake(typeNames[0])+"_gen.go"),
+			PackagePath: pkg.PkgPath,
+			TypeNames:   typeNames,
+			Code:        code,
+		})
+	}
+
+	if len(diagnostics) > 0 {
+		return nil, diagnostics
+	}
+	for _, typeName := range cfg.TypeNames {
+		if !found[typeName] {
+			return nil, fmt.Errorf("%s: %w", typeName, ErrNoSuchType)
+		}
+	}
+	if len(files) <= 0 {
+		return nil, ErrNoTypeName
+	}
+	return files, nil
+}
+
+// generateFile generates the code of the methods of given types in the package.
+func generateFile(pkg *packages.Package, typeNames []string, cfg Config) ([]byte, error) {
+	header := " Code generated by taqc; DO NOT EDIT."
+	if len(cfg.Args) > 0 {
+		header = fmt.Sprintf(" Code generated by taqc %s; DO NOT EDIT.", strings.Join(cfg.Args, " "))
+	}
+	rootStmt := g.NewRoot(
+		g.NewComment(header),
+		g.NewNewline(),
+		g.NewPackage(pkg.Name),
+		g.NewNewline(),
+	)
+
+	imports := newImportRegistry(pkg.Types)
+	imports.add("net/url")
+
+	funcs := make([]g.Statement, 0)
+	var diagnostics Diagnostics
+	for i, typeName := range typeNames {
+		fields, typeDiagnostics, err := collectFields(pkg.Types, pkg.Fset, typeName, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if len(typeDiagnostics) > 0 {
+			// go through the rest of the types to report all the problems
+			diagnostics = append(diagnostics, typeDiagnostics...)
+			continue
+		}
+
+		typeFuncs, err := generateFuncs(typeName, fields, cfg.Decoder, cfg.Fallible, imports)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate code of %s: %w", typeName, err)
+		}
+		if i > 0 {
+			funcs = append(funcs, g.NewNewline())
+		}
+		funcs = append(funcs, typeFuncs...)
+	}
+	if len(diagnostics) > 0 {
+		return nil, diagnostics
+	}
+	// "fmt" is imported only when the generated code uses that; e.g. the code for the struct of only string fields doesn't
+	for _, f := range funcs {
+		funcCode, err := f.Generate(0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate code: %w", err)
+		}
+		if strings.Contains(funcCode, "fmt.") {
+			imports.add("fmt")
+			break
+		}
+	}
+
+	code, err := rootStmt.AddStatements(imports.generate()...).AddStatements(funcs...).Gofmt("-s").Generate(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate code: %w", err)
+	}
+	return []byte(code), nil
+}
+
+// collectFields collects the fields of given type, and validates those. The problems of the fields are returned as the diagnostics.
+func collectFields(pkg *types.Package, fset *token.FileSet, typeName string, cfg Config) ([]*internal.Field, Diagnostics, error) {
+	fields, err := internal.CollectQueryParameterFields(typeName, pkg, internal.Funcs{
+		Encoders:       cfg.Encoders,
+		TimeFormatters: cfg.TimeFormatters,
+		TimeParsers:    cfg.TimeParsers,
+	})
+	var fieldErrors internal.FieldErrors
+	if err != nil && !errors.As(err, &fieldErrors) {
+		return nil, nil, fmt.Errorf("failed to collect fields from files: %w", err)
+	}
+	// the fields that have no problem in the collection are validated as well, to report all the problems
+	fieldErrors = append(fieldErrors, validateFields(fields, cfg.Decoder, cfg.Fallible, pkg)...)
+	if len(fieldErrors) > 0 {
+		return nil, toDiagnostics(fieldErrors, typeName, fset), nil
+	}
+	return fields, nil, nil
+}
+
+// packageDir returns the directory of the package.
+func packageDir(pkg *packages.Package) string {
+	if len(pkg.GoFiles) > 0 {
+		return filepath.Dir(pkg.GoFiles[0])
+	}
+	return filepath.Dir(pkg.CompiledGoFiles[0])
+}
+
+// validateFields generates the code of each field in advance to find the problems of all the fields, since the generation aborts on the first problem.
+func validateFields(fields []*internal.Field, decoder bool, fallible bool, pkg *types.Package) internal.FieldErrors {
+	var errs internal.FieldErrors
+	var validate func(fields []*internal.Field)
+	validate = func(fields []*internal.Field) {
+		for _, field := range fields {
+			if field.Children != nil || field.ElemFields != nil {
+				validate(field.Children)
+				validate(field.ElemFields)
+				continue
+			}
+			err := validateField(field, decoder, fallible, newImportRegistry(pkg))
+			if err != nil {
+				errs = append(errs, &internal.FieldError{
+					Pos:       field.Pos,
+					FieldName: field.FieldName,
+					Err:       err,
+				})
+			}
+		}
+	}
+	validate(fields)
+	return errs
+}
+
+func validateField(field *internal.Field, decoder bool, fallible bool, imports *importRegistry) error {
+	if !fallible && field.EncoderFunc != "" {
+		return fmt.Errorf("%s is encoded by %s: %w", field.FieldType, field.EncoderFunc, ErrFallibleEncoding)
+	}
+	if !fallible && field.Marshaler == "MarshalText" {
+		return fmt.Errorf("%s is encoded by MarshalText(): %w", field.FieldType, ErrFallibleEncoding)
+	}
+	if !fallible && field.TimeZoneVar != "" {
+		return fmt.Errorf("the time zone %s is loaded on encoding: %w", field.TimeZone, ErrFallibleEncoding)
+	}
+
+	_, err := generateEncoderStmts([]*internal.Field{field}, "v", imports)
+	if err != nil {
+		return err
+	}
+	if decoder {
+		_, err = generateDecoderStmts([]*internal.Field{field}, "v", nil, imports)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateFuncs generates `ToQueryParameters()` method of given type, and `FromQueryParameters(url.Values) error` method if decoder is true.
+// ToQueryParameters returns an error as well if fallible is true.
+func generateFuncs(typeName string, fields []*internal.Field, decoder bool, fallible bool, imports *importRegistry) ([]g.Statement, error) {
+	signature := g.NewFuncSignature("ToQueryParameters").ReturnTypes("url.Values")
+	returnStmt := g.NewReturnStatement("qp")
+	if fallible {
+		signature = signature.ReturnTypes("url.Values", "error")
+		returnStmt = g.NewReturnStatement("qp", "nil")
+	}
+	encoderStmts, err := generateEncoderStmts(fields, "v", imports)
+	if err != nil {
+		return nil, err
+	}
+	// the time zones are loaded only by the fallible one; validateField() rejects those otherwise
+	loadStmts, err := generateTimeZoneLoadStmts(typeName, fields, encoderStmts, []string{"nil"}, imports)
+	if err != nil {
+		return nil, err
+	}
+	f := g.NewFunc(g.NewFuncReceiver("v", "*"+typeName), signature).AddStatements(loadStmts...).AddStatements(
+		g.NewRawStatement("qp := url.Values{}"),
+	).AddStatements(encoderStmts...)
+	f = f.AddStatements(returnStmt)
+
+	funcs := []g.Statement{f}
+	if decoder {
+		fromQueryParametersFunc, err := generateFromQueryParametersFunc(typeName, fields, imports)
+		if err != nil {
+			return nil, err
+		}
+		funcs = append(funcs, g.NewNewline(), fromQueryParametersFunc)
+	}
+	return funcs, nil
+}
+
+// importRegistry collects the packages that are referred by the generated code.
+type importRegistry struct {
+	pkg   *types.Package
+	paths []string
+	names map[string]string
+	// blankPaths is the import paths that are imported only for their side effects.
+	blankPaths []string
+	// timeZoneLoaders is the names of the package-level functions that load the locations, and timeZones is the IANA time zones of those.
+	timeZoneLoaders []string
+	timeZones       map[string]string
+}
+
+func newImportRegistry(pkg *types.Package) *importRegistry {
+	return &importRegistry{
+		pkg:       pkg,
+		paths:     make([]string, 0),
+		names:     map[string]string{},
+		timeZones: map[string]string{},
+	}
+}
+
+// add registers given import path.
+func (r *importRegistry) add(path string) {
+	if _, ok := r.names[path]; ok {
+		return
+	}
+	r.names[path] = ""
+	r.paths = append(r.paths, path)
+}
+
+// qualifier is a types.Qualifier that qualifies the types of other packages by their package names, and registers those packages.
+func (r *importRegistry) qualifier(p *types.Package) string {
+	if r.pkg != nil && p.Path() == r.pkg.Path() {
+		return ""
+	}
+	r.add(p.Path())
+	return p.Name()
+}
+
+// typeExpr returns the expression of given type that is valid in the generated code.
+func (r *importRegistry) typeExpr(t types.Type) string {
+	return types.TypeString(t, r.qualifier)
+}
+
+// funcExpr returns the expression of given function name that is valid in the generated code, and registers the package of that function.
+// The function of another package is given with the import path; e.g. `github.com/example/money.Encode` makes `money.Encode`.
+func (r *importRegistry) funcExpr(funcName string) string {
+	expr, path := internal.QualifyFuncName(funcName, r.pkg)
+	if path != "" {
+		r.add(path)
+	}
+	return expr
+}
+
+// addBlank registers given import path that is imported only for its side effects, i.e. `import _ "path"`.
+func (r *importRegistry) addBlank(path string) {
+	for _, p := range r.blankPaths {
+		if p == path {
+			return
+		}
+	}
+	r.blankPaths = append(r.blankPaths, path)
+}
+
+// addTimeZone registers the package-level function that loads the location of given IANA time zone for given type, and returns the name of that function.
+// The name has the type name, so that the functions of the files that are generated separately in a package don't collide.
+// The zone data is embedded by `time/tzdata`, so that the zone can be loaded on the systems that don't have that (e.g. minimal containers).
+func (r *importRegistry) addTimeZone(typeName string, timeZone string) string {
+	loaderName := fmt.Sprintf("load%sLocation_%s", strcase.ToCamel(typeName), internal.TimeZoneIdentifier(timeZone))
+	if _, ok := r.timeZones[loaderName]; ok {
+		return loaderName
+	}
+	r.add("sync")
+	r.add("time")
+	r.addBlank("time/tzdata")
+	r.timeZones[loaderName] = timeZone
+	r.timeZoneLoaders = append(r.timeZoneLoaders, loaderName)
+	return loaderName
+}
+
+// generate generates the import declarations, and the functions that load the locations.
+// Each function loads the location on the first call rather than on the initialization of the package, so that the failure is returned as an error by the generated methods.
+func (r *importRegistry) generate() []g.Statement {
+	stmts := []g.Statement{g.NewImport(r.paths...)}
+	for _, path := range r.blankPaths {
+		stmts = append(stmts, g.NewRawStatementf("import _ %q", path))
+	}
+	for _, loaderName := range r.timeZoneLoaders {
+		stmts = append(stmts, g.NewNewline(), g.NewRawStatementf(
+			"var %s = func() func() (*time.Location, error) {\nvar once sync.Once\nvar loc *time.Location\nvar err error\nreturn func() (*time.Location, error) {\nonce.Do(func() {\nloc, err = time.LoadLocation(%q)\n})\nreturn loc, err\n}\n}()",
+			loaderName, r.timeZones[loaderName],
+		))
+	}
+	return stmts
+}
+
+// integerBitSizes is the bit sizes of the integer types; 0 means the size of `int`.
+var integerBitSizes = map[string]int{
+	"int":   0,
+	"int8":  8,
+	"int16": 16,
+	"int32": 32,
+	"int64": 64,
+}
+
+// unsignedIntegerBitSizes is the bit sizes of the unsigned integer types; 0 means the size of `uint`.
+var unsignedIntegerBitSizes = map[string]int{
+	"uint":   0,
+	"uint8":  8,
+	"uint16": 16,
+	"uint32": 32,
+	"uint64": 64,
+}
+
+// floatBitSizes is the bit sizes of the float types.
+var floatBitSizes = map[string]int{
+	"float32": 32,
+	"float64": 64,
+}
+
+// durationUnitExprs is the expressions of the units that `durationUnit` option supports, except `time.Nanosecond`.
+var durationUnitExprs = map[time.Duration]string{
+	time.Microsecond: "time.Microsecond",
+	time.Millisecond: "time.Millisecond",
+	time.Second:      "time.Second",
+	time.Minute:      "time.Minute",
+	time.Hour:        "time.Hour",
+}
+
+// splitFieldType splits the field type into the container part (i.e. "", "*", or "[]") and the element type.
+// The container is decided by the underlying type, so a named slice type is also regarded as a slice.
+func splitFieldType(t types.Type) (string, types.Type) {
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		return "*", u.Elem()
+	case *types.Slice:
+		return "[]", u.Elem()
+	}
+	return "", t
+}
+
+// getElemKind returns the kind of given element type: "time.Time", "time.Duration", or the name of the underlying basic type (e.g. "int64").
+// If the element type is not supported, this returns an empty string.
+func getElemKind(t types.Type) string {
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "time" && (obj.Name() == "Time" || obj.Name() == "Duration") {
+			return "time." + obj.Name()
+		}
+	}
+
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return ""
+	}
+	// normalize the aliases (i.e. byte and rune)
+	name := types.Typ[basic.Kind()].Name()
+	if name == "string" || name == "bool" {
+		return name
+	}
+	if _, ok := integerBitSizes[name]; ok {
+		return name
+	}
+	if _, ok := unsignedIntegerBitSizes[name]; ok {
+		return name
+	}
+	if _, ok := floatBitSizes[name]; ok {
+		return name
+	}
+	return ""
+}
+
+// generateValueFormatterExpr returns the function that generates the expression to convert given value expression to string.
+// If the element type is not supported, this returns nil.
+func generateValueFormatterExpr(field *internal.Field, elemType types.Type, elemKind string, imports *importRegistry) func(valueExpr string) string {
+	if elemKind == "bool" {
+		needsConversion := !types.Identical(elemType, types.Typ[types.Bool])
+		if field.BoolFormat == taqcinternal.BoolFormatTrueFalse {
+			imports.add("strconv")
+			return func(valueExpr string) string {
+				if needsConversion {
+					valueExpr = fmt.Sprintf("bool(%s)", valueExpr)
+				}
+				return fmt.Sprintf("strconv.FormatBool(%s)", valueExpr)
+			}
+		}
+		trueValue, falseValue := field.BoolFormat.Values()
+		return func(valueExpr string) string {
+			if needsConversion {
+				valueExpr = fmt.Sprintf("bool(%s)", valueExpr)
+			}
+			return fmt.Sprintf("func(b bool) string {\nif b {\nreturn %q\n}\nreturn %q\n}(%s)", trueValue, falseValue, valueExpr)
+		}
+	}
+
+	if elemKind == "string" {
+		if types.Identical(elemType, types.Typ[types.String]) {
+			return func(valueExpr string) string {
+				return valueExpr
+			}
+		}
+		return func(valueExpr string) string {
+			return fmt.Sprintf("string(%s)", valueExpr)
+		}
+	}
+
+	if _, ok := integerBitSizes[elemKind]; ok {
+		return func(valueExpr string) string {
+			return fmt.Sprintf(`fmt.Sprintf("%%d", %s)`, valueExpr)
+		}
+	}
+	if _, ok := unsignedIntegerBitSizes[elemKind]; ok {
+		return func(valueExpr string) string {
+			return fmt.Sprintf(`fmt.Sprintf("%%d", %s)`, valueExpr)
+		}
+	}
+
+	if bitSize, ok := floatBitSizes[elemKind]; ok {
+		if field.FloatFormat == 0 { // for compatibility
+			return func(valueExpr string) string {
+				return fmt.Sprintf(`fmt.Sprintf("%%f", %s)`, valueExpr)
+			}
+		}
+		imports.add("strconv")
+		needsConversion := !types.Identical(elemType, types.Typ[types.Float64])
+		return func(valueExpr string) string {
+			if needsConversion {
+				valueExpr = fmt.Sprintf("float64(%s)", valueExpr)
+			}
+			return fmt.Sprintf("strconv.FormatFloat(%s, '%c', %d, %d)", valueExpr, field.FloatFormat, field.FloatPrecision, bitSize)
+		}
+	}
+
+	if elemKind == "time.Duration" {
+		if field.DurationUnit == 0 {
+			return func(valueExpr string) string {
+				return fmt.Sprintf("%s.String()", valueExpr)
+			}
+		}
+		imports.add("strconv")
+		if field.DurationUnit == time.Nanosecond {
+			return func(valueExpr string) string {
+				return fmt.Sprintf("strconv.FormatInt(int64(%s), 10)", valueExpr)
+			}
+		}
+		imports.add("time")
+		unitExpr := durationUnitExprs[field.DurationUnit]
+		return func(valueExpr string) string {
+			return fmt.Sprintf("strconv.FormatInt(int64(%s/%s), 10)", valueExpr, unitExpr)
+		}
+	}
+
+	if elemKind == "time.Time" {
+		imports.add("time")
+		for _, path := range field.FuncImports {
+			imports.add(path)
+		}
+		timeFormatter, _ := field.TimeFormatterStmt.Generate(0)
+		return func(valueExpr string) string {
+			return fmt.Sprintf("%s(%s)", strings.TrimRight(timeFormatter, "\n"), valueExpr)
+		}
+	}
+
+	return nil
+}
+
+// generateValueParserExpr returns the function that generates the expression to parse given string expression,
+// and the function that generates the expression to convert the parsed value to the element type.
+// If the element type doesn't need parsing (i.e. string and bool), the parser is nil.
+// If the element type is not supported, this returns nil for both; if the field lacks what the parsing needs (e.g. the time parser),
+// or the element type is encoded by the marshaler but cannot be decoded, this returns an error.
+func generateValueParserExpr(field *internal.Field, elemType types.Type, elemKind string, imports *importRegistry) (func(strExpr string) string, func(parsedExpr string) string, error) {
+	noConversion := func(parsedExpr string) string {
+		return parsedExpr
+	}
+	// parsedType is the basic type that is returned by the parser; the conversion is unnecessary when the element type is identical to that.
+	var parsedType types.Type
+
+	if internal.ImplementsTextUnmarshaler(elemType) {
+		typeExpr := imports.typeExpr(elemType)
+		return func(strExpr string) string {
+			return fmt.Sprintf("func(s string) (%s, error) {\nvar value %s\nerr := value.UnmarshalText([]byte(s))\nreturn value, err\n}(%s)", typeExpr, typeExpr, strExpr)
+		}, noConversion, nil
+	}
+	if marshaler := internal.LookupMarshaler(elemType); marshaler != "" {
+		// the value that the marshaler encodes cannot be parsed back by the kind of the type
+		return nil, nil, fmt.Errorf("%s has no UnmarshalText() to decode the value that %s() encodes: %w", imports.typeExpr(elemType), marshaler, taqc.ErrUnsupportedFieldType)
+	}
+
+	switch elemKind {
+	case "":
+		return nil, nil, nil
+	case "string":
+		parsedType = types.Typ[types.String]
+	case "bool":
+		// convert the bool expression to the element type if necessary
+		toElemType := func(boolExpr string) string {
+			if types.Identical(elemType, types.Typ[types.Bool]) {
+				return boolExpr
+			}
+			return fmt.Sprintf("%s(%s)", imports.typeExpr(elemType), boolExpr)
+		}
+		switch field.BoolFormat {
+		case taqcinternal.BoolFormatOne:
+			return nil, func(strExpr string) string {
+				return toElemType(fmt.Sprintf(`%s == "1"`, strExpr))
+			}, nil
+		case taqcinternal.BoolFormatPresence:
+			return nil, func(strExpr string) string {
+				return toElemType("true")
+			}, nil
+		}
+		trueValue, falseValue := field.BoolFormat.Values()
+		return func(strExpr string) string {
+			return fmt.Sprintf("func(s string) (bool, error) {\nswitch s {\ncase %q:\nreturn true, nil\ncase %q:\nreturn false, nil\n}\nreturn false, fmt.Errorf(\"%%q is not a bool value in %s format\", s)\n}(%s)", trueValue, falseValue, field.BoolFormat, strExpr)
+		}, toElemType, nil
+	case "time.Duration":
+		imports.add("time")
+		if field.DurationUnit == 0 {
+			return func(strExpr string) string {
+				return fmt.Sprintf("time.ParseDuration(%s)", strExpr)
+			}, noConversion, nil
+		}
+		imports.add("strconv")
+		parser := func(strExpr string) string {
+			return fmt.Sprintf("strconv.ParseInt(%s, 10, 64)", strExpr)
+		}
+		if field.DurationUnit == time.Nanosecond {
+			return parser, func(parsedExpr string) string {
+				return fmt.Sprintf("time.Duration(%s)", parsedExpr)
+			}, nil
+		}
+		unitExpr := durationUnitExprs[field.DurationUnit]
+		return parser, func(parsedExpr string) string {
+			return fmt.Sprintf("time.Duration(%s) * %s", parsedExpr, unitExpr)
+		}, nil
+	case "time.Time":
+		imports.add("time")
+		if field.TimeParserStmt == nil {
+			return nil, nil, fmt.Errorf("no parser is given for the time formatter %s; give that by -time-parser option: %s", field.TimeFormatter, field.FieldName)
+		}
+		for _, path := range field.FuncImports {
+			imports.add(path)
+		}
+		timeParser, _ := field.TimeParserStmt.Generate(0)
+		if strings.Contains(timeParser, "strconv.") {
+			imports.add("strconv")
+		}
+		return func(strExpr string) string {
+			return fmt.Sprintf("%s(%s)", strings.TrimRight(timeParser, "\n"), strExpr)
+		}, noConversion, nil
+	}
+
+	var parser func(strExpr string) string
+	if bitSize, ok := integerBitSizes[elemKind]; ok {
+		parsedType = types.Typ[types.Int64]
+		parser = func(strExpr string) string {
+			return fmt.Sprintf("strconv.ParseInt(%s, 10, %d)", strExpr, bitSize)
+		}
+	} else if bitSize, ok := unsignedIntegerBitSizes[elemKind]; ok {
+		parsedType = types.Typ[types.Uint64]
+		parser = func(strExpr string) string {
+			return fmt.Sprintf("strconv.ParseUint(%s, 10, %d)", strExpr, bitSize)
+		}
+	} else if bitSize, ok := floatBitSizes[elemKind]; ok {
+		parsedType = types.Typ[types.Float64]
+		parser = func(strExpr string) string {
+			return fmt.Sprintf("strconv.ParseFloat(%s, %d)", strExpr, bitSize)
+		}
+	}
+	if parser != nil {
+		imports.add("strconv")
+	}
+
+	if types.Identical(elemType, parsedType) {
+		return parser, noConversion, nil
+	}
+	return parser, func(parsedExpr string) string {
+		return fmt.Sprintf("%s(%s)", imports.typeExpr(elemType), parsedExpr)
+	}, nil
+}
+
+// generateEncoderStmts generates the statements that encode given fields of the receiver expression into `qp`.
+func generateEncoderStmts(fields []*internal.Field, receiverExpr string, imports *importRegistry) ([]g.Statement, error) {
+	stmts := make([]g.Statement, 0)
+	for _, field := range fields {
+		fieldExpr := receiverExpr + "." + field.FieldName
+		paramName := generateParamNameExpr(field.ParamName, imports)
+
+		if field.Children != nil {
+			childStmts, err := generateEncoderStmts(field.Children, fieldExpr, imports)
+			if err != nil {
+				return nil, err
+			}
+			if container, _ := splitFieldType(field.Type); container == "*" {
+				stmts = append(stmts, g.NewIf(fmt.Sprintf("%s != nil", fieldExpr), childStmts...))
+			} else {
+				stmts = append(stmts, childStmts...)
+			}
+			continue
+		}
+
+		if field.ElemFields != nil {
+			sliceStmts, err := generateSliceOfStructEncoderStmts(field, fieldExpr, imports)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, sliceStmts...)
+			continue
+		}
+
+		if mapType, ok := field.Type.Underlying().(*types.Map); ok {
+			mapStmts, err := generateMapEncoderStmts(field, fieldExpr, mapType, imports)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, mapStmts...)
+			continue
+		}
+
+		if field.EncoderFunc != "" || field.Marshaler != "" {
+			stmts = append(stmts, generateCustomEncoderStmts(field, fieldExpr, imports)...)
+			continue
+		}
+
+		container, elemType := splitFieldType(field.Type)
+		elemKind := getElemKind(elemType)
+		if elemKind == "bool" && field.BoolFormat.OmitsFalse() {
+			trueValue, _ := field.BoolFormat.Values()
+			switch container {
+			case "":
+				stmts = append(stmts,
+					g.NewIf(
+						fieldExpr,
+						g.NewRawStatementf(`qp.Set(%s, %q)`, paramName, trueValue),
+					),
+				)
+				continue
+			case "*":
+				stmts = append(stmts,
+					g.NewIf(
+						fmt.Sprintf("%s != nil && *%s", fieldExpr, fieldExpr),
+						g.NewRawStatementf(`qp.Set(%s, %q)`, paramName, trueValue),
+					),
+				)
+				continue
+			}
+		}
+		if elemKind == "bool" && container == "[]" && !field.BoolFormat.SupportsCollection() {
+			return nil, fmt.Errorf("boolFormat=%s doesn't support the slice: %s", field.BoolFormat, field.FieldType)
+		}
+
+		formatterExpr := generateValueFormatterExpr(field, elemType, elemKind, imports)
+		if formatterExpr == nil {
+			return nil, fmt.Errorf("%s is unsupported: %w", field.FieldType, taqc.ErrUnsupportedFieldType)
+		}
+
+		switch container {
+		case "":
+			stmts = append(stmts, wrapWithOmitCondition(field, fieldExpr, elemType, imports, g.NewRawStatementf(`qp.Set(%s, %s)`, paramName, formatterExpr(fieldExpr))))
+		case "*":
+			stmts = append(stmts,
+				g.NewIf(
+					fmt.Sprintf("%s != nil", fieldExpr),
+					g.NewRawStatementf(`qp.Set(%s, %s)`, paramName, formatterExpr("*"+fieldExpr)),
+				),
+			)
+		case "[]":
+			sliceFieldName := generateLocalVarName(fieldExpr, "slice")
+			sliceStmts, blankParamName := generateSliceEncoderStmts(field, sliceFieldName, formatterExpr, imports)
+			stmts = append(stmts, g.NewCodeBlock(append([]g.Statement{g.NewRawStatementf("%s := %s", sliceFieldName, fieldExpr)}, sliceStmts...)...))
+			if field.KeepEmpty && !field.OmitEmpty {
+				stmts = append(stmts,
+					g.NewIf(
+						fmt.Sprintf("%s != nil && len(%s) <= 0", fieldExpr, fieldExpr),
+						g.NewRawStatementf(`qp.Set(%s, "")`, blankParamName),
+					),
+				)
+			}
+		}
+	}
+
+	return stmts, nil
+}
+
+// generateCustomEncoderStmts generates the statements that encode the field by the encoder function or the marshaler method of that, and add the values to the parameter.
+func generateCustomEncoderStmts(field *internal.Field, fieldExpr string, imports *importRegistry) []g.Statement {
+	paramName := generateParamNameExpr(field.ParamName, imports)
+	encodeStmts := generateEncodeValueStmts(field, imports)
+	addStmt := func(encodedExpr string, isMulti bool) g.Statement {
+		if isMulti {
+			return g.NewFor(fmt.Sprintf("i := 0; i < len(%s); i++", encodedExpr), g.NewRawStatementf("qp.Add(%s, %s[i])", paramName, encodedExpr))
+		}
+		return g.NewRawStatementf("qp.Set(%s, %s)", paramName, encodedExpr)
+	}
+
+	container, _ := splitFieldType(field.Type)
+	if !field.EncoderForElem {
+		container = ""
+	}
+	switch container {
+	case "[]":
+		// the values of the elements are collected into a string slice, and that is encoded according to the collection format
+		sliceStmts, blankParamName := generateSliceEncoderStmts(field, "encoded", func(valueExpr string) string {
+			return valueExpr
+		}, imports)
+		collectStmt := func(encodedExpr string, isMulti bool) g.Statement {
+			if isMulti {
+				return g.NewRawStatementf("encoded = append(encoded, %s...)", encodedExpr)
+			}
+			return g.NewRawStatementf("encoded = append(encoded, %s)", encodedExpr)
+		}
+		stmts := []g.Statement{
+			g.NewCodeBlock(append([]g.Statement{
+				g.NewRawStatementf("encoded := make([]string, 0, len(%s))", fieldExpr),
+				g.NewFor(
+					fmt.Sprintf("i := 0; i < len(%s); i++", fieldExpr),
+					encodeStmts(fieldExpr+"[i]", collectStmt)...,
+				),
+			}, sliceStmts...)...),
+		}
+		if field.KeepEmpty && !field.OmitEmpty {
+			stmts = append(stmts,
+				g.NewIf(
+					fmt.Sprintf("%s != nil && len(%s) <= 0", fieldExpr, fieldExpr),
+					g.NewRawStatementf(`qp.Set(%s, "")`, blankParamName),
+				),
+			)
+		}
+		return stmts
+	case "*":
+		valueExpr := fieldExpr // a method is callable through the pointer as it is
+		if field.EncoderFunc != "" {
+			valueExpr = "*" + fieldExpr
+		}
+		return []g.Statement{
+			g.NewIf(fmt.Sprintf("%s != nil", fieldExpr), encodeStmts(valueExpr, addStmt)...),
+		}
+	default:
+		if cond := generateOmitCondition(field, fieldExpr, field.Type, imports); cond != "" {
+			return []g.Statement{g.NewIf(cond, encodeStmts(fieldExpr, addStmt)...)}
+		}
+		return []g.Statement{g.NewCodeBlock(encodeStmts(fieldExpr, addStmt)...)}
+	}
+}
+
+// generateEncodeValueStmts returns the function that generates the statements to encode given value expression by the encoder function or the marshaler method of the field.
+// The generated statements pass the expression of the encoded value to addStmt; isMulti reports whether that expression is a string slice rather than a string.
+func generateEncodeValueStmts(field *internal.Field, imports *importRegistry) func(valueExpr string, addStmt func(encodedExpr string, isMulti bool) g.Statement) []g.Statement {
+	errorExpr := generateEncodeErrorExpr(field.ParamName, imports)
+	switch {
+	case field.EncoderFunc != "":
+		funcExpr := imports.funcExpr(field.EncoderFunc)
+		return func(valueExpr string, addStmt func(encodedExpr string, isMulti bool) g.Statement) []g.Statement {
+			return []g.Statement{
+				g.NewRawStatementf("values, err := %s(%s)", funcExpr, valueExpr),
+				g.NewIf("err != nil", g.NewReturnStatement("nil", errorExpr)),
+				addStmt("values", true),
+			}
+		}
+	case field.Marshaler == "MarshalText":
+		return func(valueExpr string, addStmt func(encodedExpr string, isMulti bool) g.Statement) []g.Statement {
+			return []g.Statement{
+				g.NewRawStatementf("text, err := %s.MarshalText()", valueExpr),
+				g.NewIf("err != nil", g.NewReturnStatement("nil", errorExpr)),
+				addStmt("string(text)", false),
+			}
+		}
+	default:
+		return func(valueExpr string, addStmt func(encodedExpr string, isMulti bool) g.Statement) []g.Statement {
+			return []g.Statement{addStmt(fmt.Sprintf("%s.%s()", valueExpr, field.Marshaler), false)}
+		}
+	}
+}
+
+// generateSliceEncoderStmts generates the statements that encode the slice of given variable according to the collection format of the field.
+// This returns the parameter name for the blank parameter of `keepEmpty` option as well.
+func generateSliceEncoderStmts(field *internal.Field, sliceVarName string, formatterExpr func(valueExpr string) string, imports *importRegistry) ([]g.Statement, string) {
+	paramName := generateParamNameExpr(field.ParamName, imports)
+	loopCond := fmt.Sprintf("i := 0; i < len(%s); i++", sliceVarName)
+	valueExpr := formatterExpr(sliceVarName + "[i]")
+
+	switch field.CollectionFormat {
+	case taqcinternal.CollectionFormatBrackets:
+		bracketsParamName := generateParamNameExpr(field.ParamName+"[]", imports)
+		return []g.Statement{
+			g.NewFor(loopCond, g.NewRawStatementf(`qp.Add(%s, %s)`, bracketsParamName, valueExpr)),
+		}, bracketsParamName
+	case taqcinternal.CollectionFormatIndexed:
+		imports.add("strconv")
+		return []g.Statement{
+			g.NewFor(loopCond, g.NewRawStatementf(`qp.Set(%s+strconv.Itoa(i)+"]", %s)`, generateParamNameExpr(field.ParamName+"[", imports), valueExpr)),
+		}, paramName
+	case taqcinternal.CollectionFormatCSV, taqcinternal.CollectionFormatSSV, taqcinternal.CollectionFormatPipes:
+		imports.add("strings")
+		delimiter := field.CollectionFormat.Delimiter()
+		return []g.Statement{
+			g.NewIf(
+				fmt.Sprintf("len(%s) > 0", sliceVarName),
+				g.NewRawStatementf("values := make([]string, len(%s))", sliceVarName),
+				g.NewFor(
+					loopCond,
+					g.NewRawStatementf(`values[i] = strings.ReplaceAll(strings.ReplaceAll(%s, "%%", "%%25"), %q, %q)`, valueExpr, delimiter, field.CollectionFormat.EscapedDelimiter()),
+				),
+				g.NewRawStatementf(`qp.Set(%s, strings.Join(values, %q))`, paramName, delimiter),
+			),
+		}, paramName
+	default:
+		return []g.Statement{
+			g.NewFor(loopCond, g.NewRawStatementf(`qp.Add(%s, %s)`, paramName, valueExpr)),
+		}, paramName
+	}
+}
+
+// generateSliceOfStructEncoderStmts generates the statements that encode each element of the slice of struct field with the index of that.
+func generateSliceOfStructEncoderStmts(field *internal.Field, fieldExpr string, imports *importRegistry) ([]g.Statement, error) {
+	if len(field.ElemFields) <= 0 {
+		return nil, nil
+	}
+
+	// the index is numbered only for the elements that add any parameter, so that the decoder, which stops at the first missing index, takes all of them
+	indexVarName := generateIndexVarName(strings.Count(field.ParamName, taqcinternal.IndexPlaceholder))
+	elemVarName := generateLocalVarName(fieldExpr, "elem")
+	paramCountVarName := generateLocalVarName(fieldExpr, "paramCount")
+	stmts := make([]g.Statement, 0)
+	_, elemType := splitFieldType(field.Type)
+	if elemContainer, _ := splitFieldType(elemType); elemContainer == "*" {
+		stmts = append(stmts, g.NewIf(fmt.Sprintf("%s == nil", elemVarName), g.NewRawStatement("continue")))
+	}
+	stmts = append(stmts, g.NewRawStatementf("%s := len(qp)", paramCountVarName))
+	elemStmts, err := generateEncoderStmts(field.ElemFields, elemVarName, imports)
+	if err != nil {
+		return nil, err
+	}
+	stmts = append(stmts, elemStmts...)
+	stmts = append(stmts, g.NewIf(fmt.Sprintf("len(qp) > %s", paramCountVarName), g.NewRawStatementf("%s++", indexVarName)))
+
+	return []g.Statement{
+		g.NewCodeBlock(
+			g.NewRawStatementf("%s := 0", indexVarName),
+			g.NewFor(fmt.Sprintf("_, %s := range %s", elemVarName, fieldExpr), stmts...),
+		),
+	}, nil
+}
+
+// generateMapEncoderStmts generates the statements that encode the map field in the order of the keys.
+func generateMapEncoderStmts(field *internal.Field, fieldExpr string, mapType *types.Map, imports *importRegistry) ([]g.Statement, error) {
+	if getElemKind(mapType.Key()) != "string" {
+		return nil, fmt.Errorf("%s is unsupported: %w", field.FieldType, taqc.ErrUnsupportedFieldType)
+	}
+
+	keysName := generateLocalVarName(fieldExpr, "keys")
+	keyExpr := "k"
+	if !types.Identical(mapType.Key(), types.Typ[types.String]) {
+		keyExpr = "string(k)"
+	}
+	paramNameExpr := keyExpr
+	if field.MapPrefix != "" {
+		paramNameExpr = fmt.Sprintf("%s + %s", generateParamNameExpr(field.MapPrefix, imports), paramNameExpr)
+	}
+	if field.MapSuffix != "" {
+		paramNameExpr = fmt.Sprintf("%s + %s", paramNameExpr, generateParamNameExpr(field.MapSuffix, imports))
+	}
+	valueExpr := fmt.Sprintf("%s[k]", fieldExpr)
+
+	var encodeStmt g.Statement
+	container, elemType := splitFieldType(mapType.Elem())
+	elemKind := getElemKind(elemType)
+	switch {
+	case elemKind == "bool" && container == "" && field.BoolFormat.OmitsFalse():
+		trueValue, _ := field.BoolFormat.Values()
+		encodeStmt = g.NewIf(valueExpr, g.NewRawStatementf(`qp.Set(%s, %q)`, paramNameExpr, trueValue))
+	case container == "*" || (elemKind == "bool" && container == "[]" && !field.BoolFormat.SupportsCollection()):
+		return nil, fmt.Errorf("%s is unsupported: %w", field.FieldType, taqc.ErrUnsupportedFieldType)
+	default:
+		formatterExpr := generateValueFormatterExpr(field, elemType, elemKind, imports)
+		if formatterExpr == nil {
+			return nil, fmt.Errorf("%s is unsupported: %w", field.FieldType, taqc.ErrUnsupportedFieldType)
+		}
+		if container == "[]" {
+			encodeStmt = g.NewFor(
+				fmt.Sprintf("i := 0; i < len(%s); i++", valueExpr),
+				g.NewRawStatementf("qp.Add(%s, %s)", paramNameExpr, formatterExpr(valueExpr+"[i]")),
+			)
+		} else {
+			encodeStmt = g.NewRawStatementf("qp.Set(%s, %s)", paramNameExpr, formatterExpr(valueExpr))
+		}
+	}
+
+	imports.add("sort")
+	return []g.Statement{
+		g.NewCodeBlock(
+			g.NewRawStatementf("%s := make([]%s, 0, len(%s))", keysName, imports.typeExpr(mapType.Key()), fieldExpr),
+			g.NewFor(fmt.Sprintf("k := range %s", fieldExpr), g.NewRawStatementf("%s = append(%s, k)", keysName, keysName)),
+			g.NewRawStatementf("sort.Slice(%s, func(i, j int) bool { return %s[i] < %s[j] })", keysName, keysName, keysName),
+			g.NewFor(fmt.Sprintf("_, k := range %s", keysName), encodeStmt),
+		),
+	}, nil
+}
+
+// generateParamNameExpr generates the string expression of given parameter name.
+// The placeholders of the indices in the name are replaced with the index variables of the enclosing loops (see generateIndexVarName), from the outermost one.
+func generateParamNameExpr(paramName string, imports *importRegistry) string {
+	parts := strings.Split(paramName, taqcinternal.IndexPlaceholder)
+	expr := strconv.Quote(parts[0])
+	for level, part := range parts[1:] {
+		imports.add("strconv")
+		expr += fmt.Sprintf("+strconv.Itoa(%s)", generateIndexVarName(level))
+		if part != "" {
+			expr += "+" + strconv.Quote(part)
+		}
+	}
+	return expr
+}
+
+// generateIndexVarName generates the name of the index variable of the loop over the slice of struct at given nesting level; e.g. `i0` for the outermost one.
+func generateIndexVarName(level int) string {
+	return fmt.Sprintf("i%d", level)
+}
+
+// generateParseErrorExpr generates the expression of the error to return when the parsing of given parameter fails; that refers to `err`.
+func generateParseErrorExpr(paramName string, imports *importRegistry) string {
+	if !strings.Contains(paramName, taqcinternal.IndexPlaceholder) {
+		return fmt.Sprintf(`fmt.Errorf("failed to parse a query parameter %s: %%w", err)`, paramName)
+	}
+	return fmt.Sprintf(`fmt.Errorf("failed to parse a query parameter %%s: %%w", %s, err)`, generateParamNameExpr(paramName, imports))
+}
+
+// generateEncodeErrorExpr generates the expression of the error to return when the encoding of given parameter fails; that refers to `err`.
+func generateEncodeErrorExpr(paramName string, imports *importRegistry) string {
+	if !strings.Contains(paramName, taqcinternal.IndexPlaceholder) {
+		return fmt.Sprintf(`fmt.Errorf("failed to encode a query parameter %s: %%w", err)`, paramName)
+	}
+	return fmt.Sprintf(`fmt.Errorf("failed to encode a query parameter %%s: %%w", %s, err)`, generateParamNameExpr(paramName, imports))
+}
+
+// generateLocalVarName generates the name of a local variable for given field expression; e.g. `v.Filter.Tags` and `slice` make `filterTagsSlice`.
+// The names of different fields can be the same (e.g. `v.FooBar` and `v.Foo.Bar`), so the statements that declare those must be scoped in a block for each field.
+func generateLocalVarName(fieldExpr string, suffix string) string {
+	return strcase.ToLowerCamel(fmt.Sprintf("%s_%s", strings.ReplaceAll(strings.TrimPrefix(fieldExpr, "v."), ".", "_"), suffix))
+}
+
+// wrapWithOmitCondition wraps given statement with the condition that comes from `omitempty` and `omitzero` options of the field.
+func wrapWithOmitCondition(field *internal.Field, fieldExpr string, fieldType types.Type, imports *importRegistry, stmt g.Statement) g.Statement {
+	cond := generateOmitCondition(field, fieldExpr, fieldType, imports)
+	if cond == "" {
+		return stmt
+	}
+	return g.NewIf(cond, stmt)
+}
+
+// generateOmitCondition generates the condition to encode the field according to `omitempty` and `omitzero` options of that.
+// If the field is always encoded, this returns an empty string.
+func generateOmitCondition(field *internal.Field, fieldExpr string, fieldType types.Type, imports *importRegistry) string {
+	var cond string
+	elemKind := getElemKind(fieldType)
+	switch elemKind {
+	case "string":
+		if field.OmitEmpty || field.OmitZero {
+			cond = fmt.Sprintf(`%s != ""`, fieldExpr)
+		}
+	case "time.Duration":
+		if field.OmitEmpty || field.OmitZero {
+			cond = fmt.Sprintf("%s != 0", fieldExpr)
+		}
+	case "bool":
+		if field.OmitEmpty || field.OmitZero {
+			cond = fieldExpr
+		}
+	default:
+		_, isInteger := integerBitSizes[elemKind]
+		_, isUnsignedInteger := unsignedIntegerBitSizes[elemKind]
+		_, isFloat := floatBitSizes[elemKind]
+		if (isInteger || isUnsignedInteger || isFloat) && (field.OmitEmpty || field.OmitZero) {
+			cond = fmt.Sprintf("%s != 0", fieldExpr)
+		}
+	}
+
+	if cond == "" && field.OmitZero {
+		if internal.ImplementsZeroer(fieldType) { // e.g. time.Time
+			cond = fmt.Sprintf("!%s.IsZero()", fieldExpr)
+		} else if _, ok := fieldType.Underlying().(*types.Struct); ok && types.Comparable(fieldType) {
+			cond = fmt.Sprintf("%s != (%s{})", fieldExpr, imports.typeExpr(fieldType))
+		}
+	}
+	return cond
+}
+
+// generateFromQueryParametersFunc generates `(v *T) FromQueryParameters(qp url.Values) error` method.
+// The packages that the generated code uses are registered to given import registry.
+func generateFromQueryParametersFunc(typeName string, fields []*internal.Field, imports *importRegistry) (*g.Func, error) {
+	decoderStmts, err := generateDecoderStmts(fields, "v", nil, imports)
+	if err != nil {
+		return nil, err
+	}
+	loadStmts, err := generateTimeZoneLoadStmts(typeName, fields, decoderStmts, nil, imports)
+	if err != nil {
+		return nil, err
+	}
+	f := g.NewFunc(g.NewFuncReceiver("v", "*"+typeName), g.NewFuncSignature("FromQueryParameters").AddParameters(g.NewFuncParameter("qp", "url.Values")).ReturnTypes("error"))
+	f = f.AddStatements(loadStmts...).AddStatements(decoderStmts...)
+
+	f = f.AddStatements(g.NewReturnStatement("nil"))
+
+	return f, nil
+}
+
+// generateTimeZoneLoadStmts generates the statements that load the locations of the IANA time zones of given fields into the local variables (see Field.TimeZoneVar),
+// only for the ones that given statements refer to. returnExprs are the expressions to return before the error when the loading fails.
+func generateTimeZoneLoadStmts(typeName string, fields []*internal.Field, stmts []g.Statement, returnExprs []string, imports *importRegistry) ([]g.Statement, error) {
+	code := ""
+	for _, stmt := range stmts {
+		stmtCode, err := stmt.Generate(0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate code: %w", err)
+		}
+		code += stmtCode
+	}
+
+	loadStmts := make([]g.Statement, 0)
+	loaded := map[string]bool{}
+	var load func(fields []*internal.Field)
+	load = func(fields []*internal.Field) {
+		for _, field := range fields {
+			load(field.Children)
+			load(field.ElemFields)
+			if field.TimeZoneVar == "" || loaded[field.TimeZoneVar] || !strings.Contains(code, field.TimeZoneVar) {
+				continue
+			}
+			loaded[field.TimeZoneVar] = true
+			errorExpr := fmt.Sprintf(`fmt.Errorf("failed to load the time zone %s: %%w", err)`, field.TimeZone)
+			loadStmts = append(loadStmts,
+				g.NewRawStatementf("%s, err := %s()", field.TimeZoneVar, imports.addTimeZone(typeName, field.TimeZone)),
+				g.NewIf("err != nil", g.NewReturnStatement(append(returnExprs[:len(returnExprs):len(returnExprs)], errorExpr)...)),
+			)
+		}
+	}
+	load(fields)
+	return loadStmts, nil
+}
+
+// generateDecoderStmts generates the statements that decode `qp` into given fields of the receiver expression.
+// allocStmts are the statements that allocate the nested structs of pointer, which must be run before assigning a value to the field.
+func generateDecoderStmts(fields []*internal.Field, receiverExpr string, allocStmts []g.Statement, imports *importRegistry) ([]g.Statement, error) {
+	stmts := make([]g.Statement, 0)
+	for _, field := range fields {
+		fieldExpr := receiverExpr + "." + field.FieldName
+		paramName := field.ParamName
+
+		if field.ElemFields != nil {
+			sliceStmts, err := generateSliceOfStructDecoderStmts(field, fieldExpr, allocStmts, imports)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, sliceStmts...)
+			continue
+		}
+
+		if mapType, ok := field.Type.Underlying().(*types.Map); ok {
+			mapStmts, err := generateMapDecoderStmts(field, fieldExpr, mapType, allocStmts, imports)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, mapStmts...)
+			continue
+		}
+
+		container, elemType := splitFieldType(field.Type)
+		if field.Children != nil {
+			childAllocStmts := allocStmts
+			if container == "*" {
+				allocStmt := g.NewIf(fmt.Sprintf("%s == nil", fieldExpr), g.NewRawStatementf("%s = &%s{}", fieldExpr, imports.typeExpr(elemType)))
+				childAllocStmts = append(allocStmts[:len(allocStmts):len(allocStmts)], allocStmt)
+			}
+			childStmts, err := generateDecoderStmts(field.Children, fieldExpr, childAllocStmts, imports)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, childStmts...)
+			continue
+		}
+
+		elemKind := getElemKind(elemType)
+		parserExpr, conversionExpr, err := generateValueParserExpr(field, elemType, elemKind, imports)
+		if err != nil {
+			return nil, err
+		}
+		if conversionExpr == nil || (container == "[]" && elemKind == "bool" && !field.BoolFormat.SupportsCollection()) {
+			return nil, fmt.Errorf("%s is unsupported: %w", field.FieldType, taqc.ErrUnsupportedFieldType)
+		}
+
+		// assignStmts generates the statements that assign given expression to the field, allocating the nested structs beforehand.
+		assignStmts := func(valueExpr string) []g.Statement {
+			return append(allocStmts[:len(allocStmts):len(allocStmts)], g.NewRawStatementf("%s = %s", fieldExpr, valueExpr))
+		}
+
+		// fieldStmts are scoped in a block when those declare the local variables, so that the names don't collide with the other fields' ones
+		fieldStmts := make([]g.Statement, 0)
+		scoped := false
+		paramNameExpr := generateParamNameExpr(paramName, imports)
+		paramExistsCond := fmt.Sprintf(`vs, ok := qp[%s]; ok && len(vs) > 0`, paramNameExpr)
+		blankParamCond := fmt.Sprintf(`vs, ok := qp[%s]; ok && len(vs) == 1 && vs[0] == ""`, paramNameExpr)
+		if container == "[]" {
+			switch field.CollectionFormat {
+			case taqcinternal.CollectionFormatBrackets:
+				bracketsParamNameExpr := generateParamNameExpr(paramName+"[]", imports)
+				paramExistsCond = fmt.Sprintf(`vs, ok := qp[%s]; ok && len(vs) > 0`, bracketsParamNameExpr)
+				blankParamCond = fmt.Sprintf(`vs, ok := qp[%s]; ok && len(vs) == 1 && vs[0] == ""`, bracketsParamNameExpr)
+			case taqcinternal.CollectionFormatIndexed:
+				imports.add("strconv")
+				valuesName := generateLocalVarName(fieldExpr, "values")
+				scoped = true
+				indexedParamNameExpr := generateParamNameExpr(paramName+"[", imports) + `+strconv.Itoa(i)+"]"`
+				fieldStmts = append(fieldStmts,
+					g.NewRawStatementf("%s := make([]string, 0)", valuesName),
+					g.NewFor(
+						"i := 0; ; i++",
+						g.NewIf(fmt.Sprintf(`vs, ok := qp[%s]; !ok || len(vs) <= 0`, indexedParamNameExpr), g.NewRawStatement("break")),
+						g.NewRawStatementf(`%s = append(%s, qp[%s][0])`, valuesName, valuesName, indexedParamNameExpr),
+					),
+					g.NewIf(
+						fmt.Sprintf(`vs := qp[%s]; len(%s) <= 0 && len(vs) == 1 && vs[0] == ""`, paramNameExpr, valuesName),
+						g.NewRawStatementf("%s = vs", valuesName),
+					),
+				)
+				paramExistsCond = fmt.Sprintf("vs := %s; len(vs) > 0", valuesName)
+				blankParamCond = fmt.Sprintf(`vs := %s; len(vs) == 1 && vs[0] == ""`, valuesName)
+			}
+		}
+		if field.KeepEmpty && container == "[]" {
+			fieldStmts = append(fieldStmts, g.NewIf(blankParamCond, assignStmts(imports.typeExpr(field.Type)+"{}")...))
+			paramExistsCond += ` && !(len(vs) == 1 && vs[0] == "")`
+		}
+		parseErrorExpr := generateParseErrorExpr(paramName, imports)
+		parseStmts := generateParseStmts(parserExpr, conversionExpr, parseErrorExpr)
+
+		switch container {
+		case "":
+			fieldStmts = append(fieldStmts, g.NewIf(paramExistsCond, append(parseStmts("vs[0]"), assignStmts("value")...)...))
+		case "*":
+			fieldStmts = append(fieldStmts, g.NewIf(paramExistsCond, append(parseStmts("vs[0]"), assignStmts("&value")...)...))
+		case "[]":
+			var splitStmts []g.Statement
+			if delimiter := field.CollectionFormat.Delimiter(); delimiter != "" {
+				imports.add("strings")
+				splitStmts = append(splitStmts, g.NewIf(
+					`vs[0] != ""`,
+					g.NewRawStatementf("vs = strings.Split(vs[0], %q)", delimiter),
+					g.NewFor(
+						"i := 0; i < len(vs); i++",
+						g.NewRawStatement("unescaped, err := url.PathUnescape(vs[i])"),
+						g.NewIf("err != nil", g.NewReturnStatement(parseErrorExpr)),
+						g.NewRawStatement("vs[i] = unescaped"),
+					),
+				))
+			}
+			fieldStmts = append(fieldStmts,
+				g.NewIf(paramExistsCond, splitStmts...).AddStatements(
+					g.NewRawStatementf("slice := make(%s, len(vs))", imports.typeExpr(field.Type)),
+					g.NewFor(
+						"i := 0; i < len(vs); i++",
+						append(parseStmts("vs[i]"), g.NewRawStatement("slice[i] = value"))...,
+					),
+				).AddStatements(assignStmts("slice")...),
+			)
+		}
+
+		if scoped {
+			stmts = append(stmts, g.NewCodeBlock(fieldStmts...))
+		} else {
+			stmts = append(stmts, fieldStmts...)
+		}
+	}
+
+	return stmts, nil
+}
+
+// generateSliceOfStructDecoderStmts generates the statements that decode the elements of the slice of struct field from index 0 until no parameter of the element is present.
+// allocStmts are the statements that allocate the nested structs of pointer, which must be run before assigning a value to the field.
+func generateSliceOfStructDecoderStmts(field *internal.Field, fieldExpr string, allocStmts []g.Statement, imports *importRegistry) ([]g.Statement, error) {
+	if len(field.ElemFields) <= 0 {
+		return nil, nil
+	}
+
+	indexVarName := generateIndexVarName(strings.Count(field.ParamName, taqcinternal.IndexPlaceholder))
+	sliceVarName := generateLocalVarName(fieldExpr, "slice")
+	elemVarName := generateLocalVarName(fieldExpr, "elem")
+	populatedVarName := generateLocalVarName(fieldExpr, "populated")
+
+	_, elemType := splitFieldType(field.Type)
+	elemInitExpr := imports.typeExpr(elemType) + "{}"
+	if ptr, ok := elemType.Underlying().(*types.Pointer); ok {
+		elemInitExpr = "&" + imports.typeExpr(ptr.Elem()) + "{}"
+	}
+
+	elemStmts := []g.Statement{
+		g.NewRawStatementf("%s := %s", elemVarName, elemInitExpr),
+		g.NewRawStatementf("%s := false", populatedVarName),
+	}
+	elemDecoderStmts, err := generateDecoderStmts(field.ElemFields, elemVarName, []g.Statement{g.NewRawStatementf("%s = true", populatedVarName)}, imports)
+	if err != nil {
+		return nil, err
+	}
+	elemStmts = append(elemStmts, elemDecoderStmts...)
+	elemStmts = append(elemStmts,
+		g.NewIf("!"+populatedVarName, g.NewRawStatement("break")),
+		g.NewRawStatementf("%s = append(%s, %s)", sliceVarName, sliceVarName, elemVarName),
+	)
+
+	return []g.Statement{
+		g.NewCodeBlock(
+			g.NewRawStatementf("%s := make(%s, 0)", sliceVarName, imports.typeExpr(field.Type)),
+			g.NewFor(fmt.Sprintf("%s := 0; ; %s++", indexVarName, indexVarName), elemStmts...),
+			g.NewIf(
+				fmt.Sprintf("len(%s) > 0", sliceVarName),
+				append(allocStmts[:len(allocStmts):len(allocStmts)], g.NewRawStatementf("%s = %s", fieldExpr, sliceVarName))...,
+			),
+		),
+	}, nil
+}
+
+// generateParseStmts returns the function that generates the statements that parse given string expression and assign the result to `value`.
+// errorExpr is the expression of the error to return when the parsing fails; that can refer to `err`.
+func generateParseStmts(parserExpr func(strExpr string) string, conversionExpr func(parsedExpr string) string, errorExpr string) func(strExpr string) []g.Statement {
+	return func(strExpr string) []g.Statement {
+		if parserExpr == nil {
+			return []g.Statement{g.NewRawStatementf("value := %s", conversionExpr(strExpr))}
+		}
+		return []g.Statement{
+			g.NewRawStatementf("parsed, err := %s", parserExpr(strExpr)),
+			g.NewIf("err != nil", g.NewReturnStatement(errorExpr)),
+			g.NewRawStatementf("value := %s", conversionExpr("parsed")),
+		}
+	}
+}
+
+// generateMapDecoderStmts generates the statements that decode the parameters whose names match the affixes of the map field.
+// allocStmts are the statements that allocate the nested structs of pointer, which must be run before assigning a value to the field.
+func generateMapDecoderStmts(field *internal.Field, fieldExpr string, mapType *types.Map, allocStmts []g.Statement, imports *importRegistry) ([]g.Statement, error) {
+	container, elemType := splitFieldType(mapType.Elem())
+	elemKind := getElemKind(elemType)
+	parserExpr, conversionExpr, err := generateValueParserExpr(field, elemType, elemKind, imports)
+	if err != nil {
+		return nil, err
+	}
+	if getElemKind(mapType.Key()) != "string" || conversionExpr == nil || container == "*" || (container == "[]" && elemKind == "bool" && !field.BoolFormat.SupportsCollection()) {
+		return nil, fmt.Errorf("%s is unsupported: %w", field.FieldType, taqc.ErrUnsupportedFieldType)
+	}
+	parseStmts := generateParseStmts(parserExpr, conversionExpr, `fmt.Errorf("failed to parse a query parameter %s: %w", name, err)`)
+
+	// the affixes that contain the placeholders of the indices are computed into the local variables beforehand
+	affixStmts := make([]g.Statement, 0)
+	generateAffixExprs := func(affix string, suffix string) (string, string) {
+		if !strings.Contains(affix, taqcinternal.IndexPlaceholder) {
+			return fmt.Sprintf("%q", affix), strconv.Itoa(len(affix))
+		}
+		affixName := generateLocalVarName(fieldExpr, suffix)
+		affixStmts = append(affixStmts, g.NewRawStatementf("%s := %s", affixName, generateParamNameExpr(affix, imports)))
+		return affixName, fmt.Sprintf("len(%s)", affixName)
+	}
+	prefixExpr, prefixLenExpr := generateAffixExprs(field.MapPrefix, "prefix")
+	suffixExpr, suffixLenExpr := generateAffixExprs(field.MapSuffix, "suffix")
+
+	skipConds := []string{"len(vs) <= 0"}
+	if field.MapPrefix != "" || field.MapSuffix != "" {
+		imports.add("strings")
+		affixesLenExpr := fmt.Sprintf("%s+%s", prefixLenExpr, suffixLenExpr)
+		if len(affixStmts) <= 0 {
+			affixesLenExpr = strconv.Itoa(len(field.MapPrefix) + len(field.MapSuffix))
+		}
+		skipConds = append(skipConds, fmt.Sprintf("len(name) < %s", affixesLenExpr))
+		if field.MapPrefix != "" {
+			skipConds = append(skipConds, fmt.Sprintf("!strings.HasPrefix(name, %s)", prefixExpr))
+		}
+		if field.MapSuffix != "" {
+			skipConds = append(skipConds, fmt.Sprintf("!strings.HasSuffix(name, %s)", suffixExpr))
+		}
+	}
+	if field.MapCatchAll {
+		// the parameters of the other fields
+		for i, pattern := range field.ReservedPatterns {
+			if !pattern.Indexed {
+				nameExpr, _ := generateAffixExprs(pattern.Prefix+pattern.Suffix, fmt.Sprintf("reservedPatternName%d", i))
+				if pattern.Open {
+					imports.add("strings")
+					skipConds = append(skipConds, fmt.Sprintf("strings.HasPrefix(name, %s)", nameExpr))
+					continue
+				}
+				skipConds = append(skipConds, fmt.Sprintf("name == %s", nameExpr))
+				continue
+			}
+
+			// the name is the prefix, followed by an index, followed by the suffix (and any string if the pattern is open)
+			imports.add("strings")
+			patternPrefixExpr, patternPrefixLenExpr := generateAffixExprs(pattern.Prefix, fmt.Sprintf("reservedPatternPrefix%d", i))
+			patternSuffixExpr, _ := generateAffixExprs(pattern.Suffix, fmt.Sprintf("reservedPatternSuffix%d", i))
+			suffixCond := fmt.Sprintf("rest == %s", patternSuffixExpr)
+			if pattern.Open {
+				suffixCond = fmt.Sprintf("strings.HasPrefix(rest, %s)", patternSuffixExpr)
+			}
+			skipConds = append(skipConds, fmt.Sprintf(
+				"(strings.HasPrefix(name, %s) && func(s string) bool {\nrest := strings.TrimLeft(s, \"0123456789\")\nreturn len(rest) < len(s) && %s\n}(name[%s:]))",
+				patternPrefixExpr, suffixCond, patternPrefixLenExpr,
+			))
+		}
+		// the parameters of the other map fields
+		for i, affixes := range field.ReservedAffixes {
+			imports.add("strings")
+			reservedPrefixExpr, reservedPrefixLenExpr := generateAffixExprs(affixes.Prefix, fmt.Sprintf("reservedPrefix%d", i))
+			reservedSuffixExpr, reservedSuffixLenExpr := generateAffixExprs(affixes.Suffix, fmt.Sprintf("reservedSuffix%d", i))
+			conds := []string{fmt.Sprintf("len(name) >= %s+%s", reservedPrefixLenExpr, reservedSuffixLenExpr)}
+			if affixes.Prefix != "" {
+				conds = append(conds, fmt.Sprintf("strings.HasPrefix(name, %s)", reservedPrefixExpr))
+			}
+			if affixes.Suffix != "" {
+				conds = append(conds, fmt.Sprintf("strings.HasSuffix(name, %s)", reservedSuffixExpr))
+			}
+			skipConds = append(skipConds, "("+strings.Join(conds, " && ")+")")
+		}
+	}
+
+	keyExpr := "key"
+	if !types.Identical(mapType.Key(), types.Typ[types.String]) {
+		keyExpr = fmt.Sprintf("%s(key)", imports.typeExpr(mapType.Key()))
+	}
+
+	stmts := []g.Statement{
+		g.NewIf(strings.Join(skipConds, " || "), g.NewRawStatement("continue")),
+		g.NewRawStatementf("key := name[%s : len(name)-%s]", prefixLenExpr, suffixLenExpr),
+	}
+	if field.MapPrefix == "" && field.MapSuffix == "" {
+		stmts[1] = g.NewRawStatement("key := name")
+	}
+	valueExpr := "value"
+	if container == "[]" {
+		stmts = append(stmts,
+			g.NewRawStatementf("slice := make(%s, len(vs))", imports.typeExpr(mapType.Elem())),
+			g.NewFor(
+				"i := 0; i < len(vs); i++",
+				append(parseStmts("vs[i]"), g.NewRawStatement("slice[i] = value"))...,
+			),
+		)
+		valueExpr = "slice"
+	} else {
+		stmts = append(stmts, parseStmts("vs[0]")...)
+	}
+	stmts = append(stmts, allocStmts...)
+	stmts = append(stmts,
+		g.NewIf(fmt.Sprintf("%s == nil", fieldExpr), g.NewRawStatementf("%s = make(%s)", fieldExpr, imports.typeExpr(field.Type))),
+		g.NewRawStatementf("%s[%s] = %s", fieldExpr, keyExpr, valueExpr),
+	)
+
+	if len(affixStmts) <= 0 {
+		return []g.Statement{g.NewFor("name, vs := range qp", stmts...)}, nil
+	}
+	return []g.Statement{g.NewCodeBlock(append(affixStmts, g.NewFor("name, vs := range qp", stmts...))...)}, nil
+}
@@ -0,0 +1,208 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moznion/taqc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	code, err := Generate(Config{
+		TypeNames: []string{"Query"},
+		Patterns:  []string{"./testdata/example"},
+		Decoder:   true,
+		Args:      []string{"--type=Query", "--decoder"},
+	})
+	assert.NoError(t, err)
+
+	generated := string(code)
+	assert.True(t, strings.HasPrefix(generated, "// Code generated by taqc --type=Query --decoder; DO NOT EDIT.\n"))
+	assert.Contains(t, generated, "package example\n")
+	assert.Contains(t, generated, "func (v *Query) ToQueryParameters() url.Values {")
+	assert.Contains(t, generated, "func (v *Query) FromQueryParameters(qp url.Values) error {")
+}
+
+func TestGenerate_MultipleTypes(t *testing.T) {
+	code, err := Generate(Config{
+		TypeNames: []string{"Query", "Page"},
+		Patterns:  []string{"./testdata/example"},
+	})
+	assert.NoError(t, err)
+
+	generated := string(code)
+	assert.Equal(t, 1, strings.Count(generated, "import ("))
+	assert.Contains(t, generated, "func (v *Query) ToQueryParameters() url.Values {")
+	assert.Contains(t, generated, "func (v *Page) ToQueryParameters() url.Values {")
+	assert.NotContains(t, generated, "FromQueryParameters")
+}
+
+func TestGenerateTo(t *testing.T) {
+	cfg := Config{
+		TypeNames: []string{"Page"},
+		Patterns:  []string{"./testdata/example"},
+	}
+	code, err := Generate(cfg)
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	err = GenerateTo(buf, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, code, buf.Bytes())
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	_, err := Generate(Config{
+		Patterns: []string{"./testdata/example"},
+	})
+	assert.True(t, errors.Is(err, ErrNoTypeName))
+
+	_, err = Generate(Config{
+		TypeNames: []string{"Missing"},
+		Patterns:  []string{"./testdata/example"},
+	})
+	assert.Error(t, err)
+
+	_, err = Generate(Config{
+		TypeNames: []string{"Unsupported"},
+		Patterns:  []string{"./testdata/example"},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "complex128")
+}
+
+func TestGenerate_Fallible(t *testing.T) {
+	_, err := Generate(Config{
+		TypeNames: []string{"Filter"},
+		Patterns:  []string{"./testdata/example"},
+	})
+	assert.True(t, errors.Is(err, ErrFallibleEncoding))
+
+	code, err := Generate(Config{
+		TypeNames: []string{"Filter", "Page"},
+		Patterns:  []string{"./testdata/example"},
+		Fallible:  true,
+	})
+	assert.NoError(t, err)
+
+	// the signature doesn't depend on the fields
+	generated := string(code)
+	assert.Contains(t, generated, "func (v *Filter) ToQueryParameters() (url.Values, error) {")
+	assert.Contains(t, generated, "func (v *Page) ToQueryParameters() (url.Values, error) {")
+}
+
+func TestGenerate_StringerDecoder(t *testing.T) {
+	_, err := Generate(Config{
+		TypeNames: []string{"Palette"},
+		Patterns:  []string{"./testdata/example"},
+	})
+	assert.NoError(t, err)
+
+	// the value that String() encodes cannot be decoded
+	_, err = Generate(Config{
+		TypeNames: []string{"Palette"},
+		Patterns:  []string{"./testdata/example"},
+		Decoder:   true,
+	})
+	assert.True(t, errors.Is(err, taqc.ErrUnsupportedFieldType))
+	assert.Contains(t, err.Error(), "Color has no UnmarshalText()")
+}
+
+func TestGenerate_TimeZone(t *testing.T) {
+	// the time zone is loaded on encoding, which can fail
+	_, err := Generate(Config{
+		TypeNames: []string{"Schedule"},
+		Patterns:  []string{"./testdata/example"},
+	})
+	assert.True(t, errors.Is(err, ErrFallibleEncoding))
+
+	code, err := Generate(Config{
+		TypeNames: []string{"Schedule"},
+		Patterns:  []string{"./testdata/example"},
+		Decoder:   true,
+		Fallible:  true,
+	})
+	assert.NoError(t, err)
+
+	generated := string(code)
+	assert.Contains(t, generated, `import _ "time/tzdata"`)
+	assert.Contains(t, generated, "var loadScheduleLocation_Asia_Tokyo = func() func() (*time.Location, error) {")
+	assert.Equal(t, 2, strings.Count(generated, "location_Asia_Tokyo, err := loadScheduleLocation_Asia_Tokyo()"))
+	assert.NotContains(t, generated, "panic(")
+}
+
+func TestGenerate_Alias(t *testing.T) {
+	code, err := Generate(Config{
+		TypeNames: []string{"ScheduleAlias"},
+		Patterns:  []string{"./testdata/example"},
+		Decoder:   true,
+		Fallible:  true,
+	})
+	assert.NoError(t, err)
+
+	generated := string(code)
+	assert.Contains(t, generated, "func (v *ScheduleAlias) ToQueryParameters() (url.Values, error) {")
+	assert.Contains(t, generated, "location_Asia_Tokyo, err := loadScheduleAliasLocation_Asia_Tokyo()")
+}
+
+func TestGenerate_MultiplePackages(t *testing.T) {
+	_, err := Generate(Config{
+		Patterns: []string{"./testdata/multi/..."},
+	})
+	assert.True(t, errors.Is(err, ErrMultiplePackages))
+}
+
+func TestGenerateFiles(t *testing.T) {
+	files, err := GenerateFiles(Config{
+		Patterns: []string{"./testdata/multi/..."},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	assert.Equal(t, "github.com/moznion/taqc/gen/testdata/multi/a", files[0].PackagePath)
+	assert.Equal(t, []string{"Search", "Filter"}, files[0].TypeNames)
+	assert.Equal(t, "search_gen.go", filepath.Base(files[0].Path))
+	assert.Equal(t, "a", filepath.Base(filepath.Dir(files[0].Path)))
+	assert.Contains(t, string(files[0].Code), "func (v *Filter) ToQueryParameters() url.Values {")
+	assert.NotContains(t, string(files[0].Code), "NotMarked")
+
+	assert.Equal(t, "github.com/moznion/taqc/gen/testdata/multi/b", files[1].PackagePath)
+	assert.Equal(t, []string{"Page"}, files[1].TypeNames)
+	assert.Equal(t, "page_gen.go", filepath.Base(files[1].Path))
+}
+
+func TestGenerateFiles_Tags(t *testing.T) {
+	files, err := GenerateFiles(Config{
+		Patterns: []string{"./testdata/multi/..."},
+		Tags:     []string{"taqcextra"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, files, 3)
+	assert.Equal(t, []string{"Extra"}, files[2].TypeNames)
+}
+
+func TestGenerateFiles_TypeNames(t *testing.T) {
+	files, err := GenerateFiles(Config{
+		TypeNames: []string{"NotMarked", "Other"},
+		Patterns:  []string{"./testdata/multi/..."},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, []string{"NotMarked"}, files[0].TypeNames)
+	assert.Equal(t, []string{"Other"}, files[1].TypeNames)
+
+	_, err = GenerateFiles(Config{
+		TypeNames: []string{"NotMarked", "Missing"},
+		Patterns:  []string{"./testdata/multi/..."},
+	})
+	assert.True(t, errors.Is(err, ErrNoSuchType))
+
+	_, err = GenerateFiles(Config{
+		Patterns: []string{"./testdata/multi/none"},
+	})
+	assert.True(t, errors.Is(err, ErrNoTypeName))
+}
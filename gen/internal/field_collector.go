@@ -0,0 +1,665 @@
+package internal
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+	"time"
+
+	g "github.com/moznion/gowrtr/generator"
+	"github.com/moznion/taqc"
+	"github.com/moznion/taqc/internal"
+)
+
+// Field represents a field of the structure for a constructor to be generated.
+type Field struct {
+	// FieldName is a name of the field.
+	FieldName string
+	// FieldType is a type of the field as it is written in the source code.
+	FieldType string
+	// Type is a type of the field that is resolved by the type checker.
+	Type types.Type
+	// ParamName is a query parameter's name
+	ParamName string
+	// Pos is the position of the field in the source code.
+	Pos token.Pos
+
+	TimeFormatterStmt g.Statement
+	TimeParserStmt    g.Statement
+	// TimeZone is the location to convert `time.Time` into, which comes from `timeZone` option.
+	// TimeZoneVar is the name of the local variable that the generated methods load the location of an IANA time zone into; empty for the others.
+	TimeZone    string
+	TimeZoneVar string
+	// TimeFormatter is the name of the formatter of `time.Time`, which comes from `timeFormatter` option.
+	// FuncImports is the import paths of the formatter and the parser functions that TimeFormatterStmt and TimeParserStmt call.
+	TimeFormatter string
+	FuncImports   []string
+	// DurationUnit is the unit to encode `time.Duration` as an integer, which comes from `durationUnit` option; 0 means `Duration.String()` (i.e. `durationFormat=go`).
+	DurationUnit time.Duration
+	// BoolFormat is the format to encode a bool, which comes from `boolFormat` option.
+	BoolFormat internal.BoolFormat
+	// FloatFormat and FloatPrecision are the arguments for `strconv.FormatFloat()`.
+	// FloatFormat is zero when neither `floatFormat` nor `floatPrecision` option is given.
+	FloatFormat    byte
+	FloatPrecision int
+
+	// OmitEmpty represents whether the field has `omitempty` option.
+	OmitEmpty bool
+	// OmitZero represents whether the field has `omitzero` option.
+	OmitZero bool
+	// KeepEmpty represents whether the field has `keepEmpty` option.
+	KeepEmpty bool
+	// CollectionFormat is the format to encode the slice field, which comes from `collectionFormat` option.
+	CollectionFormat internal.CollectionFormat
+
+	// EncoderFunc is the name of the function that encodes the value of the field, which comes from the mapping given to the collector.
+	// Marshaler is the name of the method that encodes the value when there is no encoder function; `MarshalText` or `String`.
+	// When EncoderForElem is true, either of them encodes each element of the pointer or the slice.
+	EncoderFunc    string
+	Marshaler      string
+	EncoderForElem bool
+
+	// MapPrefix and MapSuffix are the affixes of the parameter names of the map field; each parameter name is `MapPrefix + key + MapSuffix`.
+	MapPrefix string
+	MapSuffix string
+	// MapCatchAll reports whether the map field takes the parameters of any name in the struct (see internal.IsCatchAllMap).
+	MapCatchAll bool
+	// ReservedPatterns is the patterns of the parameter names of the other fields, and ReservedAffixes is the affixes of the other map fields.
+	// The catch-all map field doesn't take the parameters that match them on decoding.
+	ReservedPatterns []internal.ParamPattern
+	ReservedAffixes  []MapAffixes
+
+	// Children is the fields of the nested struct when the field has `inline` option or the field is an embedded struct; otherwise nil.
+	Children []*Field
+
+	// ElemFields is the fields of the element struct when the field is a slice of struct that has `inline` option; otherwise nil.
+	// Their parameter names contain the placeholder of the index of the element (i.e. `internal.IndexPlaceholder`).
+	// ElemPattern is the pattern of the parameter names of the elements.
+	ElemFields  []*Field
+	ElemPattern internal.ParamPattern
+
+	// depth is the depth of the embedded struct that has the field.
+	depth int
+}
+
+// MapAffixes is the affixes of the parameter names of a map field.
+type MapAffixes struct {
+	Prefix string
+	Suffix string
+}
+
+// Funcs is the mappings to the names of the functions that the generated code calls.
+type Funcs struct {
+	// Encoders is the mapping from the type (as it is written in the package; e.g. `Money`) to the name of the function that encodes a value of that type.
+	Encoders map[string]string
+	// TimeFormatters and TimeParsers are the mappings from the name that `timeFormatter` option refers to,
+	// to the name of the function that formats `time.Time` and the one that parses that respectively.
+	TimeFormatters map[string]string
+	TimeParsers    map[string]string
+}
+
+// CollectQueryParameterFields collects the fields that have `taqc` tag from the struct of given type name in the package.
+// When some fields have problems, this returns FieldErrors of all of them, with the rest of the fields.
+func CollectQueryParameterFields(typeName string, pkg *types.Package, funcs Funcs) ([]*Field, error) {
+	if obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName); ok {
+		if structType, ok := obj.Type().Underlying().(*types.Struct); ok {
+			// report the problems of all the fields together; the fields that have problems are excluded from the later steps
+			var errs FieldErrors
+			fields, err := collectStructFields(structType, pkg, internal.IdentityParamNamer, 0, []types.Type{obj.Type()})
+			if err != nil {
+				errs = append(errs, err.(FieldErrors)...)
+			}
+			shadowedFields, err := removeShadowedFields(fields)
+			if err != nil {
+				return fields, append(errs, err.(FieldErrors)...)
+			}
+			fields = shadowedFields
+			applyEncoderFuncs(fields, pkg, funcs.Encoders)
+			err = applyTimeFormatters(fields, pkg, funcs)
+			if err != nil {
+				errs = append(errs, err.(FieldErrors)...)
+			}
+			if len(errs) > 0 {
+				return fields, errs
+			}
+			return fields, nil
+		}
+	}
+
+	return nil, fmt.Errorf("there is no suitable struct that matches given typeName [given=%s]", typeName)
+}
+
+// collectStructFields collects the fields that have `taqc` tag from given struct type.
+// depth is the depth of the embedded struct, and visiting is the struct types that are being collected; it is used to detect a cycle of the nested structs.
+// This goes through all the fields even if some of them have problems, and returns those problems together as FieldErrors with the fields that have no problem.
+func collectStructFields(structType *types.Struct, pkg *types.Package, namer internal.ParamNamer, depth int, visiting []types.Type) ([]*Field, error) {
+	fs := make([]*Field, 0)
+	var errs FieldErrors
+	for i := 0; i < structType.NumFields(); i++ {
+		f, err := collectStructField(structType, i, pkg, namer, depth, visiting)
+		if err != nil {
+			errs = appendFieldError(errs, structType.Field(i), err)
+			continue
+		}
+		if f != nil {
+			fs = append(fs, f)
+		}
+	}
+	if len(errs) > 0 {
+		return fs, errs
+	}
+	return fs, nil
+}
+
+// collectStructField collects the i-th field of given struct type; this returns nil when the field isn't a target.
+func collectStructField(structType *types.Struct, i int, pkg *types.Package, namer internal.ParamNamer, depth int, visiting []types.Type) (*Field, error) {
+	field := structType.Field(i)
+	tag := reflect.StructTag(structType.Tag(i)).Get(internal.TagName)
+	if tag == "" {
+		if !field.Anonymous() {
+			return nil, nil
+		}
+
+		// promote the fields of the embedded struct
+		nestedType, nestedStructType := indirectStructType(field.Type())
+		if nestedStructType == nil {
+			return nil, nil
+		}
+		err := checkCyclicStruct(nestedType, pkg, visiting)
+		if err != nil {
+			return nil, err
+		}
+		children, err := collectStructFields(nestedStructType, pkg, namer, depth+1, append(visiting[:len(visiting):len(visiting)], nestedType))
+		if err != nil {
+			return nil, err
+		}
+		return &Field{
+			Pos:       field.Pos(),
+			FieldName: field.Name(),
+			FieldType: types.TypeString(field.Type(), types.RelativeTo(pkg)),
+			Type:      field.Type(),
+			Children:  children,
+			depth:     depth,
+		}, nil
+	}
+
+	parsedTag, err := internal.ParseTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldName := field.Name()
+	fieldType := types.TypeString(field.Type(), types.RelativeTo(pkg))
+	if !field.Exported() && field.Pkg() != pkg {
+		return nil, fmt.Errorf("the field is not exported [field=%s]", fieldName)
+	}
+
+	if parsedTag.ParamName == "" {
+		return nil, taqc.ErrQueryParameterNameIsEmpty
+	}
+	paramName := namer(parsedTag.ParamName)
+
+	if _, inline := parsedTag.Option("inline"); inline {
+		if sliceType, ok := field.Type().Underlying().(*types.Slice); ok {
+			elemFields, elemPattern, err := collectSliceElemFields(parsedTag, sliceType.Elem(), pkg, namer, visiting)
+			if err != nil {
+				return nil, err
+			}
+			return &Field{
+				Pos:         field.Pos(),
+				FieldName:   fieldName,
+				FieldType:   fieldType,
+				Type:        field.Type(),
+				ParamName:   paramName,
+				ElemFields:  elemFields,
+				ElemPattern: elemPattern,
+				depth:       depth,
+			}, nil
+		}
+
+		children, err := collectNestedStructFields(parsedTag, field.Type(), pkg, namer, depth, visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &Field{
+			Pos:       field.Pos(),
+			FieldName: fieldName,
+			FieldType: fieldType,
+			Type:      field.Type(),
+			ParamName: paramName,
+			Children:  children,
+			depth:     depth,
+		}, nil
+	}
+
+	timeLayout, _ := parsedTag.Option("timeLayout")
+	timeLayout = internal.ResolveTimeLayout(timeLayout)
+	unixTimeUnit, _ := parsedTag.Option("unixTimeUnit")
+	timeFormatterStmtBase := g.NewAnonymousFunc(false, g.NewAnonymousFuncSignature().AddParameters(g.NewFuncParameter("t", "time.Time")).ReturnTypes("string"))
+
+	timeFormatterStmt := timeFormatterStmtBase.Statements(g.NewReturnStatement(`fmt.Sprintf("%d", t.Unix())`))
+	if unixTimeUnit != "" {
+		switch unixTimeUnit {
+		case "sec":
+			// do nothing; using default stmt
+		case "millisec":
+			timeFormatterStmt = timeFormatterStmtBase.Statements(g.NewReturnStatement(`fmt.Sprintf("%d", t.UnixMilli())`))
+		case "microsec":
+			timeFormatterStmt = timeFormatterStmtBase.Statements(g.NewReturnStatement(`fmt.Sprintf("%d", t.UnixMicro())`))
+		case "nanosec":
+			timeFormatterStmt = timeFormatterStmtBase.Statements(g.NewReturnStatement(`fmt.Sprintf("%d", t.UnixNano())`))
+		default:
+			return nil, fmt.Errorf("%s is unsupported: %w", unixTimeUnit, taqc.ErrUnsupportedUnixTimeUnit)
+		}
+	}
+	timeZone, _ := parsedTag.Option("timeZone")
+	locExpr, locVar, err := generateTimeZoneExpr(timeZone)
+	if err != nil {
+		return nil, err
+	}
+	if timeLayout != "" { // higher priority
+		timeExpr := "t"
+		if locExpr != "" {
+			timeExpr = fmt.Sprintf("t.In(%s)", locExpr)
+		}
+		timeFormatterStmt = timeFormatterStmtBase.Statements(g.NewReturnStatement(fmt.Sprintf(`%s.Format(%q)`, timeExpr, timeLayout)))
+	}
+
+	timeParserStmt := generateTimeParserStmt(timeLayout, unixTimeUnit, locExpr)
+	timeFormatter, _ := parsedTag.Option("timeFormatter") // the highest priority; applied by applyTimeFormatters()
+
+	durationFormat, _ := parsedTag.Option("durationFormat")
+	durationUnitValue, _ := parsedTag.Option("durationUnit")
+	durationUnit, err := internal.ParseDurationFormat(durationFormat, durationUnitValue)
+	if err != nil {
+		return nil, err
+	}
+
+	floatFormat, _ := parsedTag.Option("floatFormat")
+	floatPrecision, _ := parsedTag.Option("floatPrecision")
+	floatFmt, floatPrec, err := internal.ParseFloatFormat(floatFormat, floatPrecision)
+	if err != nil {
+		return nil, err
+	}
+	if floatFormat == "" && floatPrecision == "" {
+		floatFmt = 0 // use `fmt.Sprintf("%f")` for compatibility
+	}
+
+	boolFormatValue, _ := parsedTag.Option("boolFormat")
+	boolFormat, err := internal.ParseBoolFormat(boolFormatValue)
+	if err != nil {
+		return nil, err
+	}
+
+	_, omitEmpty := parsedTag.Option("omitempty")
+	_, omitZero := parsedTag.Option("omitzero")
+	_, keepEmpty := parsedTag.Option("keepEmpty")
+
+	collectionFormatValue, _ := parsedTag.Option("collectionFormat")
+	collectionFormat, err := internal.ParseCollectionFormat(collectionFormatValue)
+	if err != nil {
+		return nil, err
+	}
+
+	var mapPrefix, mapSuffix string
+	var mapCatchAll bool
+	if _, ok := field.Type().Underlying().(*types.Map); ok {
+		mapPrefix, mapSuffix, err = internal.MapParamAffixes(parsedTag, namer)
+		if err != nil {
+			return nil, err
+		}
+		mapCatchAll = internal.IsCatchAllMap(parsedTag)
+	}
+
+	return &Field{
+		Pos:               field.Pos(),
+		FieldName:         fieldName,
+		FieldType:         fieldType,
+		Type:              field.Type(),
+		ParamName:         paramName,
+		TimeFormatterStmt: timeFormatterStmt,
+		TimeParserStmt:    timeParserStmt,
+		TimeZone:          timeZone,
+		TimeZoneVar:       locVar,
+		TimeFormatter:     timeFormatter,
+		DurationUnit:      durationUnit,
+		BoolFormat:        boolFormat,
+		FloatFormat:       floatFmt,
+		FloatPrecision:    floatPrec,
+		OmitEmpty:         omitEmpty,
+		OmitZero:          omitZero,
+		KeepEmpty:         keepEmpty,
+		CollectionFormat:  collectionFormat,
+		MapPrefix:         mapPrefix,
+		MapSuffix:         mapSuffix,
+		MapCatchAll:       mapCatchAll,
+		depth:             depth,
+	}, nil
+}
+
+// collectNestedStructFields collects the fields of the nested struct that the field with `inline` option has.
+func collectNestedStructFields(parsedTag *internal.Tag, fieldType types.Type, pkg *types.Package, namer internal.ParamNamer, depth int, visiting []types.Type) ([]*Field, error) {
+	nestedType, structType := indirectStructType(fieldType)
+	if structType == nil {
+		return nil, fmt.Errorf("inline field type is %s: %w", types.TypeString(fieldType, types.RelativeTo(pkg)), taqc.ErrUnsupportedFieldType)
+	}
+	err := checkCyclicStruct(nestedType, pkg, visiting)
+	if err != nil {
+		return nil, err
+	}
+
+	nestedNamer, err := internal.NestedParamNamer(parsedTag, namer)
+	if err != nil {
+		return nil, err
+	}
+	return collectStructFields(structType, pkg, nestedNamer, depth, append(visiting[:len(visiting):len(visiting)], nestedType))
+}
+
+// collectSliceElemFields collects the fields of the element struct of the slice of struct field that has `inline` option,
+// and returns the pattern of the parameter names of the elements as well.
+func collectSliceElemFields(parsedTag *internal.Tag, elemType types.Type, pkg *types.Package, namer internal.ParamNamer, visiting []types.Type) ([]*Field, internal.ParamPattern, error) {
+	nestedType, structType := indirectStructType(elemType)
+	if structType == nil {
+		return nil, internal.ParamPattern{}, fmt.Errorf("inline field type is []%s: %w", types.TypeString(elemType, types.RelativeTo(pkg)), taqc.ErrUnsupportedFieldType)
+	}
+	err := checkCyclicStruct(nestedType, pkg, visiting)
+	if err != nil {
+		return nil, internal.ParamPattern{}, err
+	}
+
+	elemNamer, err := internal.IndexedParamNamer(parsedTag, namer)
+	if err != nil {
+		return nil, internal.ParamPattern{}, err
+	}
+	fields, err := collectStructFields(structType, pkg, elemNamer, 0, append(visiting[:len(visiting):len(visiting)], nestedType))
+	if err != nil {
+		return nil, internal.ParamPattern{}, err
+	}
+	fields, err = removeShadowedFields(fields)
+	if err != nil {
+		return nil, internal.ParamPattern{}, err
+	}
+	return fields, internal.ElemParamPattern(elemNamer), nil
+}
+
+// indirectStructType returns the type of given struct or pointer of struct type, and its underlying struct.
+// If the type is neither of them, this returns nil for the struct.
+func indirectStructType(t types.Type) (types.Type, *types.Struct) {
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	structType, _ := t.Underlying().(*types.Struct)
+	return t, structType
+}
+
+// checkCyclicStruct returns an error when given struct type is in visiting.
+func checkCyclicStruct(t types.Type, pkg *types.Package, visiting []types.Type) error {
+	for _, v := range visiting {
+		if types.Identical(v, t) {
+			return fmt.Errorf("%s: %w", types.TypeString(t, types.RelativeTo(pkg)), taqc.ErrCyclicNestedStruct)
+		}
+	}
+	return nil
+}
+
+// removeShadowedFields removes the fields that are shadowed by the fields at shallower depths, in the manner of the promoted fields of Go.
+// The map fields are out of this, since the names of their parameters are dynamic.
+func removeShadowedFields(fields []*Field) ([]*Field, error) {
+	leaves := make([]*Field, 0)
+	mapFields := make([]*Field, 0)
+	var collectLeaves func(fields []*Field)
+	collectLeaves = func(fields []*Field) {
+		for _, field := range fields {
+			if field.Children != nil {
+				collectLeaves(field.Children)
+				continue
+			}
+			if _, ok := field.Type.Underlying().(*types.Map); ok {
+				mapFields = append(mapFields, field)
+				continue
+			}
+			leaves = append(leaves, field)
+		}
+	}
+	collectLeaves(fields)
+
+	entries := make([]internal.ParamEntry, len(leaves))
+	for i, leaf := range leaves {
+		entries[i] = internal.ParamEntry{
+			Name:  leaf.ParamName,
+			Depth: leaf.depth,
+		}
+	}
+	shadowed, err := internal.ResolveShadowedParams(entries)
+	if err != nil {
+		// report all the ambiguous fields rather than the first one
+		var errs FieldErrors
+		for _, i := range internal.FindAmbiguousParams(entries) {
+			errs = append(errs, &FieldError{
+				Pos:       leaves[i].Pos,
+				FieldName: leaves[i].FieldName,
+				Err:       fmt.Errorf("%q is duplicated: %w", leaves[i].ParamName, internal.ErrAmbiguousQueryParameter),
+			})
+		}
+		return nil, errs
+	}
+	shadowedFields := map[*Field]bool{}
+	reservedPatterns := make([]internal.ParamPattern, 0, len(leaves))
+	for i, leaf := range leaves {
+		if shadowed[i] {
+			shadowedFields[leaf] = true
+			continue
+		}
+		if leaf.ElemFields != nil {
+			reservedPatterns = append(reservedPatterns, leaf.ElemPattern)
+			continue
+		}
+		reservedPatterns = append(reservedPatterns, internal.FieldParamPatterns(leaf.ParamName, leaf.CollectionFormat)...)
+	}
+	reservedAffixes := make([]MapAffixes, 0, len(mapFields))
+	for _, mapField := range mapFields {
+		if !mapField.MapCatchAll {
+			reservedAffixes = append(reservedAffixes, MapAffixes{Prefix: mapField.MapPrefix, Suffix: mapField.MapSuffix})
+		}
+	}
+	for _, mapField := range mapFields {
+		mapField.ReservedPatterns = reservedPatterns
+		mapField.ReservedAffixes = reservedAffixes
+	}
+
+	var remove func(fields []*Field) []*Field
+	remove = func(fields []*Field) []*Field {
+		fs := make([]*Field, 0, len(fields))
+		for _, field := range fields {
+			if shadowedFields[field] {
+				continue
+			}
+			if field.Children != nil {
+				field.Children = remove(field.Children)
+			}
+			fs = append(fs, field)
+		}
+		return fs
+	}
+	return remove(fields), nil
+}
+
+// applyEncoderFuncs sets the encoder functions to the fields whose types, or the element types of the pointers or the slices, are in encoderFuncs.
+// For the other fields, this sets the marshaler methods of those types in the same manner, i.e. `encoding.TextMarshaler` or `fmt.Stringer`.
+func applyEncoderFuncs(fields []*Field, pkg *types.Package, encoderFuncs map[string]string) {
+	for _, field := range fields {
+		if field.Children != nil {
+			applyEncoderFuncs(field.Children, pkg, encoderFuncs)
+			continue
+		}
+		if field.ElemFields != nil {
+			applyEncoderFuncs(field.ElemFields, pkg, encoderFuncs)
+			continue
+		}
+		if _, ok := field.Type.Underlying().(*types.Map); ok {
+			continue
+		}
+
+		if encoderFunc, ok := encoderFuncs[types.TypeString(field.Type, types.RelativeTo(pkg))]; ok {
+			field.EncoderFunc = encoderFunc
+			continue
+		}
+		if marshaler := LookupMarshaler(field.Type); marshaler != "" {
+			field.Marshaler = marshaler
+			continue
+		}
+		var elemType types.Type
+		switch u := field.Type.Underlying().(type) {
+		case *types.Pointer:
+			elemType = u.Elem()
+		case *types.Slice:
+			elemType = u.Elem()
+		default:
+			continue
+		}
+		if encoderFunc, ok := encoderFuncs[types.TypeString(elemType, types.RelativeTo(pkg))]; ok {
+			field.EncoderFunc = encoderFunc
+			field.EncoderForElem = true
+		} else if marshaler := LookupMarshaler(elemType); marshaler != "" {
+			field.Marshaler = marshaler
+			field.EncoderForElem = true
+		}
+	}
+}
+
+// applyTimeFormatters sets the statements that call the functions of the mappings to the fields that have `timeFormatter` option.
+// The formatter function is mandatory, but the parser function is not; TimeParserStmt becomes nil when there is no parser function.
+func applyTimeFormatters(fields []*Field, pkg *types.Package, funcs Funcs) error {
+	var errs FieldErrors
+	for _, field := range fields {
+		if field.Children != nil || field.ElemFields != nil {
+			err := applyTimeFormatters(append(field.Children, field.ElemFields...), pkg, funcs)
+			if err != nil {
+				errs = append(errs, err.(FieldErrors)...)
+			}
+			continue
+		}
+		if field.TimeFormatter == "" {
+			continue
+		}
+
+		formatterFunc, ok := funcs.TimeFormatters[field.TimeFormatter]
+		if !ok {
+			errs = append(errs, &FieldError{
+				Pos:       field.Pos,
+				FieldName: field.FieldName,
+				Err:       fmt.Errorf("%s: %w", field.TimeFormatter, taqc.ErrUnknownTimeFormatter),
+			})
+			continue
+		}
+		locExpr, _, err := generateTimeZoneExpr(field.TimeZone)
+		if err != nil {
+			errs = append(errs, &FieldError{Pos: field.Pos, FieldName: field.FieldName, Err: err})
+			continue
+		}
+
+		formatterExpr, formatterPath := QualifyFuncName(formatterFunc, pkg)
+		timeExpr := "t"
+		if locExpr != "" {
+			timeExpr = fmt.Sprintf("t.In(%s)", locExpr)
+		}
+		field.TimeFormatterStmt = g.NewAnonymousFunc(false, g.NewAnonymousFuncSignature().AddParameters(g.NewFuncParameter("t", "time.Time")).ReturnTypes("string")).
+			Statements(g.NewReturnStatement(fmt.Sprintf("%s(%s)", formatterExpr, timeExpr)))
+		field.FuncImports = appendImportPath(nil, formatterPath)
+
+		field.TimeParserStmt = nil
+		parserFunc, ok := funcs.TimeParsers[field.TimeFormatter]
+		if !ok {
+			continue
+		}
+		parserExpr, parserPath := QualifyFuncName(parserFunc, pkg)
+		timeParserStmtBase := g.NewAnonymousFunc(false, g.NewAnonymousFuncSignature().AddParameters(g.NewFuncParameter("s", "string")).ReturnTypes("time.Time", "error"))
+		if locExpr == "" {
+			field.TimeParserStmt = timeParserStmtBase.Statements(g.NewReturnStatement(fmt.Sprintf("%s(s)", parserExpr)))
+		} else {
+			field.TimeParserStmt = timeParserStmtBase.Statements(
+				g.NewRawStatementf("t, err := %s(s)", parserExpr),
+				g.NewIf("err != nil", g.NewReturnStatement("time.Time{}", "err")),
+				g.NewReturnStatement(fmt.Sprintf("t.In(%s)", locExpr), "nil"),
+			)
+		}
+		field.FuncImports = appendImportPath(field.FuncImports, parserPath)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// appendImportPath appends given import path unless that is empty or already in paths.
+func appendImportPath(paths []string, path string) []string {
+	if path == "" {
+		return paths
+	}
+	for _, p := range paths {
+		if p == path {
+			return paths
+		}
+	}
+	return append(paths, path)
+}
+
+func generateTimeParserStmt(timeLayout string, unixTimeUnit string, locExpr string) g.Statement {
+	timeParserStmtBase := g.NewAnonymousFunc(false, g.NewAnonymousFuncSignature().AddParameters(g.NewFuncParameter("s", "string")).ReturnTypes("time.Time", "error"))
+
+	if timeLayout != "" { // higher priority
+		if locExpr == "" {
+			return timeParserStmtBase.Statements(g.NewReturnStatement(fmt.Sprintf(`time.Parse(%q, s)`, timeLayout)))
+		}
+		return timeParserStmtBase.Statements(
+			g.NewRawStatementf(`t, err := time.ParseInLocation(%q, s, %s)`, timeLayout, locExpr),
+			g.NewIf("err != nil", g.NewReturnStatement("time.Time{}", "err")),
+			g.NewReturnStatement(fmt.Sprintf("t.In(%s)", locExpr), "nil"),
+		)
+	}
+
+	unixTimeStmt := "time.Unix(i, 0)"
+	switch unixTimeUnit {
+	case "millisec":
+		unixTimeStmt = "time.UnixMilli(i)"
+	case "microsec":
+		unixTimeStmt = "time.UnixMicro(i)"
+	case "nanosec":
+		unixTimeStmt = "time.Unix(0, i)"
+	}
+	if locExpr != "" {
+		unixTimeStmt = fmt.Sprintf("%s.In(%s)", unixTimeStmt, locExpr)
+	}
+	return timeParserStmtBase.Statements(
+		g.NewRawStatement("i, err := strconv.ParseInt(s, 10, 64)"),
+		g.NewIf("err != nil", g.NewReturnStatement("time.Time{}", "err")),
+		g.NewReturnStatement(unixTimeStmt, "nil"),
+	)
+}
+
+// generateTimeZoneExpr generates the expression of the location of given value of `timeZone` option. If the value is empty, this returns an empty expression.
+// An IANA time zone refers to the local variable that the generated methods load the location into beforehand; this returns the name of that as well.
+func generateTimeZoneExpr(timeZone string) (string, string, error) {
+	_, err := internal.LoadTimeZone(timeZone)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch timeZone {
+	case "":
+		return "", "", nil
+	case "UTC":
+		return "time.UTC", "", nil
+	case "Local":
+		return "time.Local", "", nil
+	}
+	varName := "location_" + TimeZoneIdentifier(timeZone)
+	return varName, varName, nil
+}
+
+// TimeZoneIdentifier returns the part of the Go identifier for given IANA time zone; e.g. `America/Port-au-Prince` makes `America_Port_au_Prince`.
+func TimeZoneIdentifier(timeZone string) string {
+	return strings.NewReplacer("/", "_", "-", "_", "+", "Plus").Replace(timeZone)
+}
@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// FieldError is the error of a field of the struct, which has the position of the field in the source code.
+type FieldError struct {
+	// Pos is the position of the field.
+	Pos token.Pos
+	// FieldName is the name of the field.
+	FieldName string
+	Err       error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %s: %s", e.FieldName, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors is the errors of the fields; the collector goes through all the fields and reports the problems together.
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldError := range e {
+		messages[i] = fieldError.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// appendFieldError appends the error as the error of given field. The errors that come from the nested fields are appended as they are,
+// so that those have the positions of the nested fields.
+func appendFieldError(errs FieldErrors, field *types.Var, err error) FieldErrors {
+	var fieldErrs FieldErrors
+	if errors.As(err, &fieldErrs) {
+		return append(errs, fieldErrs...)
+	}
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		return append(errs, fieldErr)
+	}
+	return append(errs, &FieldError{
+		Pos:       field.Pos(),
+		FieldName: field.Name(),
+		Err:       err,
+	})
+}
@@ -0,0 +1,21 @@
+package internal
+
+import (
+	"go/types"
+	"strings"
+)
+
+// QualifyFuncName returns the expression of given function name that is valid in the code of the package, and the import path that the expression needs.
+// The function of another package is given with the import path; e.g. `github.com/example/money.Encode` makes `money.Encode`.
+// If the function doesn't need importing, the import path is empty.
+func QualifyFuncName(funcName string, pkg *types.Package) (string, string) {
+	i := strings.LastIndex(funcName, ".")
+	if i < 0 || i < strings.LastIndex(funcName, "/") {
+		return funcName, ""
+	}
+	path, name := funcName[:i], funcName[i+1:]
+	if pkg != nil && path == pkg.Path() {
+		return name, ""
+	}
+	return path[strings.LastIndex(path, "/")+1:] + "." + name, path
+}
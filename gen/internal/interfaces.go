@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"go/types"
+)
+
+var (
+	errorType = types.Universe.Lookup("error").Type()
+	byteSlice = types.NewSlice(types.Typ[types.Byte])
+
+	textMarshalerInterface   = newSingleMethodInterface("MarshalText", nil, []types.Type{byteSlice, errorType})
+	textUnmarshalerInterface = newSingleMethodInterface("UnmarshalText", []types.Type{byteSlice}, []types.Type{errorType})
+	stringerInterface        = newSingleMethodInterface("String", nil, []types.Type{types.Typ[types.String]})
+	zeroerInterface          = newSingleMethodInterface("IsZero", nil, []types.Type{types.Typ[types.Bool]})
+)
+
+// newSingleMethodInterface returns the interface type that has only the method of given signature.
+func newSingleMethodInterface(methodName string, params []types.Type, results []types.Type) *types.Interface {
+	toTuple := func(ts []types.Type) *types.Tuple {
+		vars := make([]*types.Var, len(ts))
+		for i, t := range ts {
+			vars[i] = types.NewParam(0, nil, "", t)
+		}
+		return types.NewTuple(vars...)
+	}
+	sig := types.NewSignature(nil, toTuple(params), toTuple(results), false)
+	return types.NewInterfaceType([]*types.Func{types.NewFunc(0, nil, methodName, sig)}, nil).Complete()
+}
+
+// LookupMarshaler returns the name of the method that encodes a value of given type: `MarshalText` when the type implements `encoding.TextMarshaler`,
+// or `String` when that implements `fmt.Stringer`. The methods that have a pointer receiver are also regarded.
+// This returns an empty string for the pointer types, the interface types, and the types of `time` package (e.g. `time.Time` and `time.Month`),
+// which keep the built-in encoding.
+func LookupMarshaler(t types.Type) string {
+	if _, ok := t.Underlying().(*types.Pointer); ok || types.IsInterface(t) || isTimePackageType(t) {
+		return ""
+	}
+
+	ptr := types.NewPointer(t)
+	if types.Implements(ptr, textMarshalerInterface) {
+		return "MarshalText"
+	}
+	if types.Implements(ptr, stringerInterface) {
+		return "String"
+	}
+	return ""
+}
+
+// ImplementsTextUnmarshaler reports whether the pointer of given type implements `encoding.TextUnmarshaler`.
+// The types of `time` package are out of this, since they have the built-in decoding.
+func ImplementsTextUnmarshaler(t types.Type) bool {
+	if types.IsInterface(t) || isTimePackageType(t) {
+		return false
+	}
+	return types.Implements(types.NewPointer(t), textUnmarshalerInterface)
+}
+
+// isTimePackageType reports whether given type is defined in `time` package.
+func isTimePackageType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "time"
+}
+
+// ImplementsZeroer reports whether given type has `IsZero() bool` method, which `omitzero` option uses.
+func ImplementsZeroer(t types.Type) bool {
+	return types.Implements(t, zeroerInterface)
+}
@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// GenerateMarker is the comment that marks the struct to generate the methods for, instead of giving the type name.
+const GenerateMarker = "//taqc:generate"
+
+// CollectMarkedTypeNames returns the names of the types that have GenerateMarker comment in the package, in the order of the declarations.
+func CollectMarkedTypeNames(pkg *packages.Package) []string {
+	typeNames := make([]string, 0)
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				doc := typeSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					// the comment of `type X struct {...}` belongs to the declaration rather than the spec
+					doc = genDecl.Doc
+				}
+				if hasGenerateMarker(doc) {
+					typeNames = append(typeNames, typeSpec.Name.Name)
+				}
+			}
+		}
+	}
+	return typeNames
+}
+
+func hasGenerateMarker(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		text := strings.TrimSpace(comment.Text)
+		if text == GenerateMarker || strings.HasPrefix(text, GenerateMarker+" ") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+var ErrNoPackageFound = errors.New("no package found")
+
+// ParsePackages parses files according to given patterns (e.g. `.`, `./...`, or file names) to get the information of the packages.
+// tags is the build tags to satisfy on loading the packages.
+func ParsePackages(patterns []string, tags []string) ([]*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName |
+			packages.NeedFiles |
+			packages.NeedCompiledGoFiles |
+			packages.NeedImports |
+			packages.NeedTypes |
+			packages.NeedTypesSizes |
+			packages.NeedSyntax |
+			packages.NeedTypesInfo,
+		Tests: false,
+	}
+	if len(tags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(tags, ",")}
+	}
+
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load package: %w", err)
+	}
+	if len(pkgs) <= 0 {
+		return nil, fmt.Errorf("%s: %w", strings.Join(patterns, " "), ErrNoPackageFound)
+	}
+	return pkgs, nil
+}
@@ -0,0 +1,19 @@
+package diagnostics
+
+import "time"
+
+type Broken struct {
+	Empty     string     `taqc:",omitempty"`
+	Since     time.Time  `taqc:"since, unixTimeUnit=minute"`
+	Value     complex128 `taqc:"value"`
+	Name      string     `taqc:"name"`
+	Duplicate string     `taqc:"name"`
+	Fine      string     `taqc:"fine"`
+	Nested    struct {
+		Value complex64 `taqc:"value"`
+	} `taqc:"nested, inline, nestStyle=dot"`
+}
+
+type AlsoBroken struct {
+	Value complex64 `taqc:"value"`
+}
@@ -0,0 +1,47 @@
+package example
+
+import (
+	"strconv"
+	"time"
+)
+
+type Query struct {
+	Name    string    `taqc:"name"`
+	Limit   int64     `taqc:"limit,omitempty"`
+	Since   time.Time `taqc:"since, unixTimeUnit=sec"`
+	Verbose bool      `taqc:"verbose"`
+}
+
+type Page struct {
+	Cursor string `taqc:"cursor"`
+}
+
+type Unsupported struct {
+	Value complex128 `taqc:"value"`
+}
+
+type Status int
+
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(strconv.Itoa(int(s))), nil
+}
+
+type Filter struct {
+	Status Status `taqc:"status"`
+}
+
+type Color int
+
+func (c Color) String() string {
+	return strconv.Itoa(int(c))
+}
+
+type Palette struct {
+	Color Color `taqc:"color"`
+}
+
+type Schedule struct {
+	Start time.Time `taqc:"start, timeLayout=2006-01-02, timeZone=Asia/Tokyo"`
+}
+
+type ScheduleAlias = Schedule
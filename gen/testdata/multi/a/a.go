@@ -0,0 +1,17 @@
+package a
+
+//taqc:generate
+type Search struct {
+	Keyword string `taqc:"q"`
+}
+
+// Filter has the marker after the doc comment.
+//
+//taqc:generate
+type Filter struct {
+	Status string `taqc:"status"`
+}
+
+type NotMarked struct {
+	Value string `taqc:"value"`
+}
@@ -0,0 +1,12 @@
+package b
+
+type (
+	//taqc:generate
+	Page struct {
+		Cursor string `taqc:"cursor"`
+	}
+
+	Other struct {
+		Value string `taqc:"value"`
+	}
+)
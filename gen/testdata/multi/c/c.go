@@ -0,0 +1,9 @@
+//go:build taqcextra
+// +build taqcextra
+
+package c
+
+//taqc:generate
+type Extra struct {
+	Value string `taqc:"value"`
+}
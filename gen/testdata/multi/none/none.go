@@ -0,0 +1,5 @@
+package none
+
+type Query struct {
+	Value string `taqc:"value"`
+}
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrUnsupportedBoolFormat = errors.New("unsupported bool format has given")
+
+// BoolFormat is the format to encode a bool to the query parameter.
+type BoolFormat string
+
+const (
+	// BoolFormatOne encodes `true` as `1` and omits `false`; this is the default format.
+	// On decoding, `1` becomes `true` and any other value becomes `false`.
+	BoolFormatOne BoolFormat = "one"
+	// BoolFormatNumeric encodes `true` as `1` and `false` as `0`.
+	BoolFormatNumeric BoolFormat = "numeric"
+	// BoolFormatTrueFalse encodes `true` as `true` and `false` as `false`.
+	BoolFormatTrueFalse BoolFormat = "truefalse"
+	// BoolFormatYesNo encodes `true` as `yes` and `false` as `no`.
+	BoolFormatYesNo BoolFormat = "yesno"
+	// BoolFormatPresence encodes `true` as a bare flag (e.g. `?verbose`) and omits `false`.
+	// On decoding, the presence of the parameter means `true` regardless of its value.
+	BoolFormatPresence BoolFormat = "presence"
+)
+
+// ParseBoolFormat parses the value of `boolFormat` option. An empty value means BoolFormatOne.
+func ParseBoolFormat(boolFormat string) (BoolFormat, error) {
+	switch f := BoolFormat(boolFormat); f {
+	case "":
+		return BoolFormatOne, nil
+	case BoolFormatOne, BoolFormatNumeric, BoolFormatTrueFalse, BoolFormatYesNo, BoolFormatPresence:
+		return f, nil
+	default:
+		return "", fmt.Errorf("%s is unsupported: %w", boolFormat, ErrUnsupportedBoolFormat)
+	}
+}
+
+// OmitsFalse returns whether the format omits the parameter of `false`; that is true for BoolFormatOne and BoolFormatPresence.
+func (f BoolFormat) OmitsFalse() bool {
+	return f == BoolFormatOne || f == BoolFormatPresence
+}
+
+// SupportsCollection returns whether the format can encode the elements of a slice; only BoolFormatPresence can't since that has no value.
+func (f BoolFormat) SupportsCollection() bool {
+	return f != BoolFormatPresence
+}
+
+// Values returns the query parameter values of `true` and `false` in the format.
+// For BoolFormatOne, the value of `false` is `0` that is used only for the elements of a slice.
+// For BoolFormatPresence, the value of `true` is blank since that is a bare flag.
+func (f BoolFormat) Values() (string, string) {
+	switch f {
+	case BoolFormatTrueFalse:
+		return "true", "false"
+	case BoolFormatYesNo:
+		return "yes", "no"
+	case BoolFormatPresence:
+		return "", ""
+	default:
+		return "1", "0"
+	}
+}
+
+// Format returns the query parameter value of given bool in the format.
+func (f BoolFormat) Format(b bool) string {
+	trueValue, falseValue := f.Values()
+	if b {
+		return trueValue
+	}
+	return falseValue
+}
+
+// Parse parses given query parameter value in the format.
+// BoolFormatOne and BoolFormatPresence accept any value, but the other formats accept only the values of `true` and `false` in that format.
+func (f BoolFormat) Parse(value string) (bool, error) {
+	switch f {
+	case BoolFormatOne:
+		return value == "1", nil
+	case BoolFormatPresence:
+		return true, nil
+	}
+
+	trueValue, falseValue := f.Values()
+	switch value {
+	case trueValue:
+		return true, nil
+	case falseValue:
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is not a bool value in %s format", value, f)
+}
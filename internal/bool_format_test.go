@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBoolFormat(t *testing.T) {
+	for _, given := range []string{"one", "numeric", "truefalse", "yesno", "presence"} {
+		f, err := ParseBoolFormat(given)
+		assert.NoError(t, err)
+		assert.Equal(t, BoolFormat(given), f)
+	}
+
+	f, err := ParseBoolFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, BoolFormatOne, f)
+
+	_, err = ParseBoolFormat("onoff")
+	assert.ErrorIs(t, err, ErrUnsupportedBoolFormat)
+}
+
+func TestBoolFormat_Format(t *testing.T) {
+	for _, c := range []struct {
+		format     BoolFormat
+		trueValue  string
+		falseValue string
+	}{
+		{BoolFormatOne, "1", "0"},
+		{BoolFormatNumeric, "1", "0"},
+		{BoolFormatTrueFalse, "true", "false"},
+		{BoolFormatYesNo, "yes", "no"},
+		{BoolFormatPresence, "", ""},
+	} {
+		assert.Equal(t, c.trueValue, c.format.Format(true), c.format)
+		assert.Equal(t, c.falseValue, c.format.Format(false), c.format)
+	}
+}
+
+func TestBoolFormat_Parse(t *testing.T) {
+	for _, c := range []struct {
+		format   BoolFormat
+		value    string
+		expected bool
+	}{
+		{BoolFormatOne, "1", true},
+		{BoolFormatOne, "true", false},
+		{BoolFormatNumeric, "0", false},
+		{BoolFormatTrueFalse, "true", true},
+		{BoolFormatTrueFalse, "false", false},
+		{BoolFormatYesNo, "yes", true},
+		{BoolFormatYesNo, "no", false},
+		{BoolFormatPresence, "", true},
+		{BoolFormatPresence, "0", true},
+	} {
+		b, err := c.format.Parse(c.value)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, b, "%s %s", c.format, c.value)
+	}
+
+	for _, c := range []struct {
+		format BoolFormat
+		value  string
+	}{
+		{BoolFormatNumeric, "true"},
+		{BoolFormatTrueFalse, "1"},
+		{BoolFormatYesNo, "Yes"},
+	} {
+		_, err := c.format.Parse(c.value)
+		assert.Error(t, err, "%s %s", c.format, c.value)
+	}
+}
@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var ErrUnsupportedCollectionFormat = errors.New("unsupported collection format has given")
+
+// CollectionFormat is the format to encode a slice to the query parameters.
+type CollectionFormat string
+
+const (
+	// CollectionFormatMulti repeats the parameter for each value; e.g. `ids=1&ids=2`.
+	CollectionFormatMulti CollectionFormat = "multi"
+	// CollectionFormatCSV joins the values by commas; e.g. `ids=1,2`.
+	CollectionFormatCSV CollectionFormat = "csv"
+	// CollectionFormatSSV joins the values by spaces; e.g. `ids=1 2`.
+	CollectionFormatSSV CollectionFormat = "ssv"
+	// CollectionFormatPipes joins the values by pipes; e.g. `ids=1|2`.
+	CollectionFormatPipes CollectionFormat = "pipes"
+	// CollectionFormatBrackets repeats the parameter with empty brackets for each value; e.g. `ids[]=1&ids[]=2`.
+	CollectionFormatBrackets CollectionFormat = "brackets"
+	// CollectionFormatIndexed puts the index of each value in the brackets; e.g. `ids[0]=1&ids[1]=2`.
+	CollectionFormatIndexed CollectionFormat = "indexed"
+)
+
+// ParseCollectionFormat parses the value of `collectionFormat` option. An empty value means CollectionFormatMulti.
+func ParseCollectionFormat(collectionFormat string) (CollectionFormat, error) {
+	switch f := CollectionFormat(collectionFormat); f {
+	case "":
+		return CollectionFormatMulti, nil
+	case CollectionFormatMulti, CollectionFormatCSV, CollectionFormatSSV, CollectionFormatPipes, CollectionFormatBrackets, CollectionFormatIndexed:
+		return f, nil
+	default:
+		return "", fmt.Errorf("%s is unsupported: %w", collectionFormat, ErrUnsupportedCollectionFormat)
+	}
+}
+
+// Delimiter returns the delimiter of the values for csv, ssv, and pipes. For the other formats, this returns an empty string.
+func (f CollectionFormat) Delimiter() string {
+	switch f {
+	case CollectionFormatCSV:
+		return ","
+	case CollectionFormatSSV:
+		return " "
+	case CollectionFormatPipes:
+		return "|"
+	default:
+		return ""
+	}
+}
+
+// EscapedDelimiter returns the percent-encoded delimiter; e.g. `%2C` for csv.
+func (f CollectionFormat) EscapedDelimiter() string {
+	return fmt.Sprintf("%%%02X", f.Delimiter())
+}
+
+// EscapeCollectionValue escapes `%` and the delimiter in given value by percent-encoding, so the joined value can be split back.
+func (f CollectionFormat) EscapeCollectionValue(value string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(value, "%", "%25"), f.Delimiter(), f.EscapedDelimiter())
+}
+
+// SplitCollectionValue splits given joined value by the delimiter, and unescapes each value.
+func (f CollectionFormat) SplitCollectionValue(value string) ([]string, error) {
+	values := strings.Split(value, f.Delimiter())
+	for i, v := range values {
+		unescaped, err := url.PathUnescape(v)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = unescaped
+	}
+	return values, nil
+}
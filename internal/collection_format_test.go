@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCollectionFormat(t *testing.T) {
+	f, err := ParseCollectionFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, CollectionFormatMulti, f)
+
+	for _, format := range []string{"multi", "csv", "ssv", "pipes", "brackets", "indexed"} {
+		f, err := ParseCollectionFormat(format)
+		assert.NoError(t, err)
+		assert.EqualValues(t, format, f)
+	}
+
+	_, err = ParseCollectionFormat("tsv")
+	assert.ErrorIs(t, err, ErrUnsupportedCollectionFormat)
+}
+
+func TestCollectionFormat_EscapeAndSplit(t *testing.T) {
+	for _, c := range []struct {
+		format           CollectionFormat
+		escapedDelimiter string
+		escapedValue     string
+	}{
+		{CollectionFormatCSV, "%2C", "a%2Cb%25 c|d"},
+		{CollectionFormatSSV, "%20", "a,b%25%20c|d"},
+		{CollectionFormatPipes, "%7C", "a,b%25 c%7Cd"},
+	} {
+		assert.Equal(t, c.escapedDelimiter, c.format.EscapedDelimiter())
+		assert.Equal(t, c.escapedValue, c.format.EscapeCollectionValue("a,b% c|d"), c.format)
+	}
+
+	values := []string{"a,b", "50%", "", "c|d e"}
+	for _, format := range []CollectionFormat{CollectionFormatCSV, CollectionFormatSSV, CollectionFormatPipes} {
+		escaped := make([]string, len(values))
+		for i, v := range values {
+			escaped[i] = format.EscapeCollectionValue(v)
+		}
+		split, err := format.SplitCollectionValue(strings.Join(escaped, format.Delimiter()))
+		assert.NoError(t, err)
+		assert.Equal(t, values, split, format)
+	}
+
+	_, err := CollectionFormatCSV.SplitCollectionValue("a,%zz")
+	assert.Error(t, err)
+}
@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrUnsupportedDurationUnit   = errors.New("unsupported duration unit has given")
+	ErrUnsupportedDurationFormat = errors.New("unsupported duration format has given")
+)
+
+// durationUnits is the units that `durationUnit` option supports.
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+// ParseDurationFormat parses the values of `durationFormat` and `durationUnit` options into the unit to encode a `time.Duration` as an integer.
+//
+// `durationUnit` supports `ns`, `us`, `ms`, `s`, `m`, and `h`; the duration is encoded as the integer in that unit, truncated toward zero.
+// `durationFormat=go` encodes the duration by `Duration.String()` (e.g. `1m30s`); this returns 0 for that.
+// When both options are empty, this returns `time.Nanosecond` for compatibility.
+func ParseDurationFormat(durationFormat string, durationUnit string) (time.Duration, error) {
+	switch durationFormat {
+	case "":
+	case "go":
+		if durationUnit != "" {
+			return 0, fmt.Errorf("durationFormat and durationUnit cannot be used together: %w", ErrMalformedTag)
+		}
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("%s is unsupported: %w", durationFormat, ErrUnsupportedDurationFormat)
+	}
+
+	if durationUnit == "" {
+		return time.Nanosecond, nil
+	}
+	unit, ok := durationUnits[durationUnit]
+	if !ok {
+		return 0, fmt.Errorf("%s is unsupported: %w", durationUnit, ErrUnsupportedDurationUnit)
+	}
+	return unit, nil
+}
@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDurationFormat(t *testing.T) {
+	for _, c := range []struct {
+		durationFormat string
+		durationUnit   string
+		expected       time.Duration
+	}{
+		{"", "", time.Nanosecond},
+		{"", "ns", time.Nanosecond},
+		{"", "us", time.Microsecond},
+		{"", "ms", time.Millisecond},
+		{"", "s", time.Second},
+		{"", "m", time.Minute},
+		{"", "h", time.Hour},
+		{"go", "", 0},
+	} {
+		unit, err := ParseDurationFormat(c.durationFormat, c.durationUnit)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, unit, c)
+	}
+
+	_, err := ParseDurationFormat("", "d")
+	assert.ErrorIs(t, err, ErrUnsupportedDurationUnit)
+	_, err = ParseDurationFormat("iso8601", "")
+	assert.ErrorIs(t, err, ErrUnsupportedDurationFormat)
+	_, err = ParseDurationFormat("go", "s")
+	assert.ErrorIs(t, err, ErrMalformedTag)
+}
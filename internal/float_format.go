@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var ErrUnsupportedFloatFormat = errors.New("unsupported float format has given")
+
+// ParseFloatFormat parses the values of `floatFormat` and `floatPrecision` options into the arguments for `strconv.FormatFloat()`.
+//
+// `floatFormat` supports `shortest` ('g'), `fixed` ('f'), and `exponent` ('e'). `floatPrecision` is the number of digits;
+// if that is empty, it uses the smallest number of digits necessary to represent the value uniquely (i.e. -1).
+// When both options are empty, this returns ('f', 6) for compatibility with `fmt.Sprintf("%f")`.
+func ParseFloatFormat(floatFormat string, floatPrecision string) (byte, int, error) {
+	if floatFormat == "" && floatPrecision == "" {
+		return 'f', 6, nil
+	}
+
+	var fmtByte byte
+	switch floatFormat {
+	case "", "fixed":
+		fmtByte = 'f'
+	case "shortest":
+		fmtByte = 'g'
+	case "exponent":
+		fmtByte = 'e'
+	default:
+		return 0, 0, fmt.Errorf("%s is unsupported: %w", floatFormat, ErrUnsupportedFloatFormat)
+	}
+
+	if floatPrecision == "" {
+		return fmtByte, -1, nil
+	}
+	prec, err := strconv.Atoi(floatPrecision)
+	if err != nil || prec < 0 {
+		return 0, 0, fmt.Errorf("precision %s is invalid: %w", floatPrecision, ErrUnsupportedFloatFormat)
+	}
+	return fmtByte, prec, nil
+}
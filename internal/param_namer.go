@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnsupportedNestStyle  = errors.New("unsupported nest style has given")
+	ErrCyclicNestedStruct    = errors.New("cyclic nested struct has come")
+	ErrUnsupportedMapStyle   = errors.New("unsupported map style has given")
+	ErrUnsupportedIndexStyle = errors.New("unsupported index style has given")
+)
+
+// ParamNamer composes the actual query parameter name from the name in the tag.
+type ParamNamer func(name string) string
+
+// IdentityParamNamer is the ParamNamer for the fields of the top-level structure; it returns given name as it is.
+func IdentityParamNamer(name string) string {
+	return name
+}
+
+// NestedParamNamer returns the ParamNamer for the fields of the nested structure that the `inline` field has.
+// parent is the ParamNamer of the structure that has the `inline` field.
+//
+// `prefix` option prepends its value to the name (e.g. `prefix=filter.` makes `filter.status`), and
+// `nestStyle` option composes the name from the parameter name of the `inline` field; `dot` makes `filter.status` and `bracket` makes `filter[status]`.
+// If neither option is given, the names of the nested fields are used as they are.
+func NestedParamNamer(parsedTag *Tag, parent ParamNamer) (ParamNamer, error) {
+	prefix, hasPrefix := parsedTag.Option("prefix")
+	nestStyle, hasNestStyle := parsedTag.Option("nestStyle")
+	if hasPrefix && hasNestStyle {
+		return nil, fmt.Errorf("prefix and nestStyle cannot be used together: %w", ErrMalformedTag)
+	}
+
+	if hasPrefix {
+		return func(name string) string {
+			return parent(prefix + name)
+		}, nil
+	}
+
+	if !hasNestStyle {
+		return parent, nil
+	}
+	base := parent(parsedTag.ParamName)
+	switch nestStyle {
+	case "dot":
+		return func(name string) string {
+			return base + "." + name
+		}, nil
+	case "bracket":
+		return func(name string) string {
+			return base + "[" + name + "]"
+		}, nil
+	default:
+		return nil, fmt.Errorf("%s is unsupported: %w", nestStyle, ErrUnsupportedNestStyle)
+	}
+}
+
+// IsCatchAllMap returns whether the map field of given tag takes the parameters of any name in the structure that has that field,
+// i.e. `mapStyle=flat` (or an empty `prefix`) without a prefix. Such a map field doesn't take the parameters of the other fields on decoding.
+func IsCatchAllMap(parsedTag *Tag) bool {
+	prefix, hasPrefix := parsedTag.Option("prefix")
+	mapStyle, _ := parsedTag.Option("mapStyle")
+	return prefix == "" && (mapStyle == "flat" || (hasPrefix && mapStyle == ""))
+}
+
+// mapKeyPlaceholder is the placeholder of a map key to derive the affixes of the parameter names from a ParamNamer.
+const mapKeyPlaceholder = "\x00"
+
+// MapParamAffixes returns the prefix and the suffix of the parameter names of the map field; each parameter name is `prefix + key + suffix`.
+// parent is the ParamNamer of the structure that has the map field.
+//
+// `mapStyle` option decides the parameter names; `deepObject` (default) makes `filter[key]`, and `flat` makes `key`.
+// `prefix` option prepends its value to the key (e.g. `prefix=filter_` makes `filter_key`); this implies `flat` style.
+func MapParamAffixes(parsedTag *Tag, parent ParamNamer) (string, string, error) {
+	prefix, hasPrefix := parsedTag.Option("prefix")
+	mapStyle, _ := parsedTag.Option("mapStyle")
+
+	var namer ParamNamer
+	switch mapStyle {
+	case "", "deepObject":
+		if hasPrefix {
+			if mapStyle != "" {
+				return "", "", fmt.Errorf("prefix and mapStyle=deepObject cannot be used together: %w", ErrMalformedTag)
+			}
+			namer = func(key string) string {
+				return parent(prefix + key)
+			}
+			break
+		}
+		base := parent(parsedTag.ParamName)
+		namer = func(key string) string {
+			return base + "[" + key + "]"
+		}
+	case "flat":
+		namer = func(key string) string {
+			return parent(prefix + key)
+		}
+	default:
+		return "", "", fmt.Errorf("%s is unsupported: %w", mapStyle, ErrUnsupportedMapStyle)
+	}
+
+	affixes := strings.SplitN(namer(mapKeyPlaceholder), mapKeyPlaceholder, 2)
+	return affixes[0], affixes[1], nil
+}
+
+// IndexPlaceholder is the placeholder of the index of an element in the parameter names that IndexedParamNamer composes.
+const IndexPlaceholder = "\x01"
+
+// IndexedParamNamer returns the ParamNamer for the fields of the elements of the slice of struct field that has `inline` option.
+// parent is the ParamNamer of the structure that has the slice field.
+// The composed names contain IndexPlaceholder in place of the index of the element; FillIndex and FillIndices replace that with the actual index.
+//
+// `indexStyle` option decides the notation of the index; `bracket` (default) makes `items[0]` and `dot` makes `items.0`.
+// `nestStyle` option decides the separator between the index and the name; `dot` (default) makes `items[0].sku` and `bracket` makes `items[0][sku]`.
+func IndexedParamNamer(parsedTag *Tag, parent ParamNamer) (ParamNamer, error) {
+	if _, hasPrefix := parsedTag.Option("prefix"); hasPrefix {
+		return nil, fmt.Errorf("prefix cannot be used for a slice of struct: %w", ErrMalformedTag)
+	}
+
+	base := parent(parsedTag.ParamName)
+	indexStyle, _ := parsedTag.Option("indexStyle")
+	switch indexStyle {
+	case "", "bracket":
+		base += "[" + IndexPlaceholder + "]"
+	case "dot":
+		base += "." + IndexPlaceholder
+	default:
+		return nil, fmt.Errorf("%s is unsupported: %w", indexStyle, ErrUnsupportedIndexStyle)
+	}
+
+	nestStyle, _ := parsedTag.Option("nestStyle")
+	switch nestStyle {
+	case "", "dot":
+		return func(name string) string {
+			return base + "." + name
+		}, nil
+	case "bracket":
+		return func(name string) string {
+			return base + "[" + name + "]"
+		}, nil
+	default:
+		return nil, fmt.Errorf("%s is unsupported: %w", nestStyle, ErrUnsupportedNestStyle)
+	}
+}
+
+// FillIndex replaces the last IndexPlaceholder in given name with the index.
+// The placeholders of the outer slices precede that of the inner one, so this fills the index of the innermost slice.
+func FillIndex(name string, index int) string {
+	i := strings.LastIndex(name, IndexPlaceholder)
+	if i < 0 {
+		return name
+	}
+	return name[:i] + strconv.Itoa(index) + name[i+len(IndexPlaceholder):]
+}
+
+// FillIndices replaces the IndexPlaceholders in given name with the indices in order, i.e. from the outermost slice.
+func FillIndices(name string, indices []int) string {
+	for _, index := range indices {
+		name = strings.Replace(name, IndexPlaceholder, strconv.Itoa(index), 1)
+	}
+	return name
+}
@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNestedParamNamer(t *testing.T) {
+	parent := func(name string) string {
+		return "page[" + name + "]"
+	}
+
+	for _, c := range []struct {
+		tag                string
+		expected           string
+		expectedWithParent string
+	}{
+		{"filter, inline", "status", "page[status]"},
+		{"filter, inline, prefix=filter.", "filter.status", "page[filter.status]"},
+		{"filter, inline, nestStyle=dot", "filter.status", "page[filter].status"},
+		{"filter, inline, nestStyle=bracket", "filter[status]", "page[filter][status]"},
+	} {
+		tag, err := ParseTag(c.tag)
+		assert.NoError(t, err)
+		namer, err := NestedParamNamer(tag, IdentityParamNamer)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, namer("status"), c.tag)
+
+		namer, err = NestedParamNamer(tag, parent)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expectedWithParent, namer("status"), c.tag)
+	}
+}
+
+func TestNestedParamNamer_ShouldRaiseError(t *testing.T) {
+	tag, _ := ParseTag("filter, inline, nestStyle=INVALID")
+	_, err := NestedParamNamer(tag, IdentityParamNamer)
+	assert.ErrorIs(t, err, ErrUnsupportedNestStyle)
+
+	tag, _ = ParseTag("filter, inline, nestStyle=dot, prefix=f.")
+	_, err = NestedParamNamer(tag, IdentityParamNamer)
+	assert.ErrorIs(t, err, ErrMalformedTag)
+}
+
+func TestMapParamAffixes(t *testing.T) {
+	for _, c := range []struct {
+		tag            string
+		expectedPrefix string
+		expectedSuffix string
+	}{
+		{"filter", "filter[", "]"},
+		{"filter, mapStyle=deepObject", "filter[", "]"},
+		{"filter, mapStyle=flat", "", ""},
+		{"filter, mapStyle=flat, prefix=f_", "f_", ""},
+		{"filter, prefix=f_", "f_", ""},
+	} {
+		tag, err := ParseTag(c.tag)
+		assert.NoError(t, err)
+		prefix, suffix, err := MapParamAffixes(tag, IdentityParamNamer)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expectedPrefix, prefix, c.tag)
+		assert.Equal(t, c.expectedSuffix, suffix, c.tag)
+	}
+
+	tag, _ := ParseTag("filter")
+	prefix, suffix, err := MapParamAffixes(tag, func(name string) string {
+		return "q[" + name + "]"
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "q[filter][", prefix)
+	assert.Equal(t, "]", suffix)
+}
+
+func TestMapParamAffixes_ShouldRaiseError(t *testing.T) {
+	tag, _ := ParseTag("filter, mapStyle=INVALID")
+	_, _, err := MapParamAffixes(tag, IdentityParamNamer)
+	assert.ErrorIs(t, err, ErrUnsupportedMapStyle)
+
+	tag, _ = ParseTag("filter, mapStyle=deepObject, prefix=f_")
+	_, _, err = MapParamAffixes(tag, IdentityParamNamer)
+	assert.ErrorIs(t, err, ErrMalformedTag)
+}
+
+func TestIsCatchAllMap(t *testing.T) {
+	for _, c := range []struct {
+		tag      string
+		expected bool
+	}{
+		{"filter", false},
+		{"filter, mapStyle=deepObject", false},
+		{"filter, mapStyle=flat", true},
+		{"filter, mapStyle=flat, prefix=", true},
+		{"filter, prefix=", true},
+		{"filter, mapStyle=flat, prefix=f_", false},
+		{"filter, prefix=f_", false},
+	} {
+		tag, err := ParseTag(c.tag)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, IsCatchAllMap(tag), c.tag)
+	}
+}
+
+func TestIndexedParamNamer(t *testing.T) {
+	parent := func(name string) string {
+		return "page[" + name + "]"
+	}
+
+	for _, c := range []struct {
+		tag                string
+		expected           string
+		expectedWithParent string
+	}{
+		{"items, inline", "items[0].sku", "page[items][0].sku"},
+		{"items, inline, indexStyle=bracket, nestStyle=dot", "items[0].sku", "page[items][0].sku"},
+		{"items, inline, nestStyle=bracket", "items[0][sku]", "page[items][0][sku]"},
+		{"items, inline, indexStyle=dot", "items.0.sku", "page[items].0.sku"},
+		{"items, inline, indexStyle=dot, nestStyle=bracket", "items.0[sku]", "page[items].0[sku]"},
+	} {
+		tag, err := ParseTag(c.tag)
+		assert.NoError(t, err)
+		namer, err := IndexedParamNamer(tag, IdentityParamNamer)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, FillIndex(namer("sku"), 0), c.tag)
+
+		namer, err = IndexedParamNamer(tag, parent)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expectedWithParent, FillIndices(namer("sku"), []int{0}), c.tag)
+	}
+}
+
+func TestIndexedParamNamer_ShouldRaiseError(t *testing.T) {
+	tag, _ := ParseTag("items, inline, indexStyle=INVALID")
+	_, err := IndexedParamNamer(tag, IdentityParamNamer)
+	assert.ErrorIs(t, err, ErrUnsupportedIndexStyle)
+
+	tag, _ = ParseTag("items, inline, nestStyle=INVALID")
+	_, err = IndexedParamNamer(tag, IdentityParamNamer)
+	assert.ErrorIs(t, err, ErrUnsupportedNestStyle)
+
+	tag, _ = ParseTag("items, inline, prefix=i_")
+	_, err = IndexedParamNamer(tag, IdentityParamNamer)
+	assert.ErrorIs(t, err, ErrMalformedTag)
+}
+
+func TestFillIndex(t *testing.T) {
+	name := "items[" + IndexPlaceholder + "].parts[" + IndexPlaceholder + "].sku"
+	assert.Equal(t, "items["+IndexPlaceholder+"].parts[2].sku", FillIndex(name, 2))
+	assert.Equal(t, "items[1].parts[2].sku", FillIndex(FillIndex(name, 2), 1))
+	assert.Equal(t, "items[1].parts[2].sku", FillIndices(name, []int{1, 2}))
+	assert.Equal(t, "items[1].parts["+IndexPlaceholder+"].sku", FillIndices(name, []int{1}))
+	assert.Equal(t, "sku", FillIndex("sku", 1))
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"strings"
+)
+
+// ParamPattern is the pattern of the parameter names that a field produces; the map field that has no affixes doesn't take the parameters that match that.
+// A name matches the pattern when that is Prefix, followed by an index (i.e. digits) if Indexed, followed by Suffix, followed by any string if Open.
+// Prefix and Suffix can contain IndexPlaceholders of the enclosing slices of struct, which are filled with the indices on matching.
+type ParamPattern struct {
+	Prefix  string
+	Indexed bool
+	Suffix  string
+	Open    bool
+}
+
+// Matches returns whether given parameter name matches the pattern. The placeholders of the indices in the pattern are filled with given indices.
+func (p ParamPattern) Matches(paramName string, indices []int) bool {
+	prefix, suffix := FillIndices(p.Prefix, indices), FillIndices(p.Suffix, indices)
+	if !strings.HasPrefix(paramName, prefix) {
+		return false
+	}
+	rest := paramName[len(prefix):]
+	if p.Indexed {
+		index := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+		if index <= 0 {
+			return false
+		}
+		rest = rest[index:]
+	}
+	if p.Open {
+		return strings.HasPrefix(rest, suffix)
+	}
+	return rest == suffix
+}
+
+// FieldParamPatterns returns the patterns of the parameter names of the field of given parameter name.
+// A slice field produces the names according to given collection format (e.g. `ids[]` of `brackets` and `ids[0]` of `indexed`),
+// and the name as it is for the blank parameter of `keepEmpty` option.
+func FieldParamPatterns(paramName string, collectionFormat CollectionFormat) []ParamPattern {
+	patterns := []ParamPattern{{Prefix: paramName}}
+	switch collectionFormat {
+	case CollectionFormatBrackets:
+		patterns = append(patterns, ParamPattern{Prefix: paramName + "[]"})
+	case CollectionFormatIndexed:
+		patterns = append(patterns, ParamPattern{Prefix: paramName + "[", Indexed: true, Suffix: "]"})
+	}
+	return patterns
+}
+
+// ElemParamPattern returns the pattern of the parameter names of the elements of the slice of struct field; e.g. `items[0].sku` and `items[1].parts[0][name]`.
+// elemNamer is the ParamNamer that IndexedParamNamer returns for the field.
+func ElemParamPattern(elemNamer ParamNamer) ParamPattern {
+	elemPrefix := strings.SplitN(elemNamer(mapKeyPlaceholder), mapKeyPlaceholder, 2)[0]
+	i := strings.LastIndex(elemPrefix, IndexPlaceholder)
+	return ParamPattern{
+		Prefix:  elemPrefix[:i],
+		Indexed: true,
+		Suffix:  elemPrefix[i+len(IndexPlaceholder):],
+		Open:    true,
+	}
+}
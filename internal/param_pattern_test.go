@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamPattern_Matches(t *testing.T) {
+	for _, c := range []struct {
+		pattern  ParamPattern
+		name     string
+		expected bool
+	}{
+		{ParamPattern{Prefix: "ids"}, "ids", true},
+		{ParamPattern{Prefix: "ids"}, "ids[]", false},
+		{ParamPattern{Prefix: "ids[]"}, "ids[]", true},
+		{ParamPattern{Prefix: "ids[", Indexed: true, Suffix: "]"}, "ids[0]", true},
+		{ParamPattern{Prefix: "ids[", Indexed: true, Suffix: "]"}, "ids[12]", true},
+		{ParamPattern{Prefix: "ids[", Indexed: true, Suffix: "]"}, "ids[]", false},
+		{ParamPattern{Prefix: "ids[", Indexed: true, Suffix: "]"}, "ids[a]", false},
+		{ParamPattern{Prefix: "ids[", Indexed: true, Suffix: "]"}, "ids[0].x", false},
+		{ParamPattern{Prefix: "items[", Indexed: true, Suffix: "].", Open: true}, "items[0].sku", true},
+		{ParamPattern{Prefix: "items[", Indexed: true, Suffix: "].", Open: true}, "items[3].parts[0][name]", true},
+		{ParamPattern{Prefix: "items[", Indexed: true, Suffix: "].", Open: true}, "items[x].sku", false},
+		{ParamPattern{Prefix: "items[", Indexed: true, Suffix: "].", Open: true}, "items", false},
+	} {
+		assert.Equal(t, c.expected, c.pattern.Matches(c.name, nil), c.name)
+	}
+
+	pattern := ParamPattern{Prefix: "items[" + IndexPlaceholder + "].ids[", Indexed: true, Suffix: "]"}
+	assert.True(t, pattern.Matches("items[1].ids[0]", []int{1}))
+	assert.False(t, pattern.Matches("items[0].ids[0]", []int{1}))
+}
+
+func TestFieldParamPatterns(t *testing.T) {
+	assert.Equal(t, []ParamPattern{{Prefix: "ids"}}, FieldParamPatterns("ids", CollectionFormatMulti))
+	assert.Equal(t, []ParamPattern{{Prefix: "ids"}, {Prefix: "ids[]"}}, FieldParamPatterns("ids", CollectionFormatBrackets))
+	assert.Equal(t, []ParamPattern{{Prefix: "ids"}, {Prefix: "ids[", Indexed: true, Suffix: "]"}}, FieldParamPatterns("ids", CollectionFormatIndexed))
+}
+
+func TestElemParamPattern(t *testing.T) {
+	for _, c := range []struct {
+		tag      string
+		expected ParamPattern
+	}{
+		{"items, inline", ParamPattern{Prefix: "items[", Indexed: true, Suffix: "].", Open: true}},
+		{"items, inline, nestStyle=bracket", ParamPattern{Prefix: "items[", Indexed: true, Suffix: "][", Open: true}},
+		{"items, inline, indexStyle=dot", ParamPattern{Prefix: "items.", Indexed: true, Suffix: ".", Open: true}},
+	} {
+		tag, err := ParseTag(c.tag)
+		assert.NoError(t, err)
+		namer, err := IndexedParamNamer(tag, IdentityParamNamer)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, ElemParamPattern(namer), c.tag)
+	}
+}
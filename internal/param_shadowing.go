@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrAmbiguousQueryParameter = errors.New("ambiguous query parameter has come")
+
+// ParamEntry is a query parameter that a field of the structure represents.
+type ParamEntry struct {
+	// Name is the actual query parameter name.
+	Name string
+	// Depth is the depth of the embedded structure that has the field; it is 0 for the fields of the top-level structure.
+	Depth int
+}
+
+// ResolveShadowedParams reports whether each parameter is shadowed, in the manner of the promoted fields of Go;
+// a parameter at a shallower depth shadows the parameters that have the same name at deeper depths.
+//
+// This returns an error when multiple parameters have the same name at the shallowest depth, since that is ambiguous.
+func ResolveShadowedParams(entries []ParamEntry) ([]bool, error) {
+	minDepths, counts := countShallowestParams(entries)
+
+	shadowed := make([]bool, len(entries))
+	for i, entry := range entries {
+		if entry.Depth > minDepths[entry.Name] {
+			shadowed[i] = true
+			continue
+		}
+		if counts[entry.Name] > 1 {
+			return nil, fmt.Errorf("%q: %w", entry.Name, ErrAmbiguousQueryParameter)
+		}
+	}
+	return shadowed, nil
+}
+
+// FindAmbiguousParams returns the indices of the parameters that make ResolveShadowedParams fail;
+// that is, the parameters that have the same name as a preceding one at the shallowest depth.
+func FindAmbiguousParams(entries []ParamEntry) []int {
+	minDepths, counts := countShallowestParams(entries)
+
+	ambiguous := make([]int, 0)
+	seen := map[string]bool{}
+	for i, entry := range entries {
+		if entry.Depth > minDepths[entry.Name] || counts[entry.Name] <= 1 {
+			continue
+		}
+		if seen[entry.Name] {
+			ambiguous = append(ambiguous, i)
+		}
+		seen[entry.Name] = true
+	}
+	return ambiguous
+}
+
+// countShallowestParams returns the shallowest depth of each parameter name, and the number of the parameters at that depth.
+func countShallowestParams(entries []ParamEntry) (map[string]int, map[string]int) {
+	minDepths := map[string]int{}
+	counts := map[string]int{}
+	for _, entry := range entries {
+		depth, ok := minDepths[entry.Name]
+		if !ok || entry.Depth < depth {
+			minDepths[entry.Name] = entry.Depth
+			counts[entry.Name] = 1
+		} else if entry.Depth == depth {
+			counts[entry.Name]++
+		}
+	}
+	return minDepths, counts
+}
@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveShadowedParams(t *testing.T) {
+	shadowed, err := ResolveShadowedParams([]ParamEntry{
+		{Name: "foo", Depth: 1},
+		{Name: "foo", Depth: 0},
+		{Name: "bar", Depth: 1},
+		{Name: "foo", Depth: 2},
+		{Name: "buz", Depth: 2},
+		{Name: "buz", Depth: 1},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false, false, true, true, false}, shadowed)
+}
+
+func TestResolveShadowedParams_ShouldRaiseErrorWhenAmbiguous(t *testing.T) {
+	_, err := ResolveShadowedParams([]ParamEntry{
+		{Name: "foo", Depth: 1},
+		{Name: "foo", Depth: 1},
+		{Name: "foo", Depth: 2},
+	})
+	assert.ErrorIs(t, err, ErrAmbiguousQueryParameter)
+
+	// the ambiguous parameters are fine when those are shadowed
+	_, err = ResolveShadowedParams([]ParamEntry{
+		{Name: "foo", Depth: 1},
+		{Name: "foo", Depth: 1},
+		{Name: "foo", Depth: 0},
+	})
+	assert.NoError(t, err)
+}
+
+func TestFindAmbiguousParams(t *testing.T) {
+	assert.Equal(t, []int{1, 4}, FindAmbiguousParams([]ParamEntry{
+		{Name: "foo", Depth: 0},
+		{Name: "foo", Depth: 0},
+		{Name: "bar", Depth: 1},
+		{Name: "bar", Depth: 0},
+		{Name: "foo", Depth: 0},
+		{Name: "buz", Depth: 1},
+		{Name: "buz", Depth: 2},
+	}))
+	assert.Empty(t, FindAmbiguousParams([]ParamEntry{
+		{Name: "foo", Depth: 1},
+		{Name: "foo", Depth: 1},
+		{Name: "foo", Depth: 0},
+	}))
+}
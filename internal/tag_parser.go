@@ -0,0 +1,167 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrMalformedTag        = errors.New("malformed tag value has come")
+	ErrUnknownTagOption    = errors.New("unknown tag option has come")
+	ErrDuplicatedTagOption = errors.New("duplicated tag option has come")
+)
+
+// knownTagOptions is the set of the option names that are allowed in a tag.
+var knownTagOptions = map[string]bool{
+	"timeLayout":       true,
+	"unixTimeUnit":     true,
+	"omitempty":        true,
+	"omitzero":         true,
+	"keepEmpty":        true,
+	"floatFormat":      true,
+	"floatPrecision":   true,
+	"inline":           true,
+	"prefix":           true,
+	"nestStyle":        true,
+	"mapStyle":         true,
+	"collectionFormat": true,
+	"indexStyle":       true,
+	"durationUnit":     true,
+	"durationFormat":   true,
+	"timeZone":         true,
+	"timeFormatter":    true,
+	"boolFormat":       true,
+}
+
+// Tag represents a parsed `taqc` tag value.
+type Tag struct {
+	// ParamName is a query parameter's name.
+	ParamName string
+	// Options is the options that follow the parameter name. An option without value (i.e. a flag) has an empty string.
+	Options map[string]string
+}
+
+// Option returns the value of given option name, and whether that option is present.
+func (t *Tag) Option(name string) (string, bool) {
+	v, ok := t.Options[name]
+	return v, ok
+}
+
+// ParseTag parses a `taqc` tag value.
+//
+// The grammar is `paramName[, option[=value]]...`. Surrounding spaces of each element are trimmed.
+// A value can be quoted by single quotes to contain commas and spaces as they are (e.g. `timeLayout='Mon, 02 Jan 2006'`),
+// and a backslash escapes the following character both inside and outside the quotes.
+//
+// This returns an error when the tag is malformed, or it contains an unknown or duplicated option.
+func ParseTag(tagValue string) (*Tag, error) {
+	segments, err := splitTagSegments(tagValue)
+	if err != nil {
+		return nil, err
+	}
+
+	paramName, err := unquoteTagToken(strings.TrimSpace(segments[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &Tag{
+		ParamName: paramName,
+		Options:   map[string]string{},
+	}
+	for _, segment := range segments[1:] {
+		segment = strings.TrimSpace(segment)
+		if segment == "" {
+			continue
+		}
+
+		rawName, rawValue := segment, ""
+		if i := indexOfUnquoted(segment, '='); i >= 0 {
+			rawName, rawValue = segment[:i], segment[i+1:]
+		}
+
+		name := strings.TrimSpace(rawName)
+		if !knownTagOptions[name] {
+			return nil, fmt.Errorf("%q: %w", name, ErrUnknownTagOption)
+		}
+		if _, exists := tag.Options[name]; exists {
+			return nil, fmt.Errorf("%q: %w", name, ErrDuplicatedTagOption)
+		}
+
+		value, err := unquoteTagToken(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, err
+		}
+		tag.Options[name] = value
+	}
+
+	return tag, nil
+}
+
+// splitTagSegments splits the tag value by the commas that are neither quoted nor escaped.
+// Each segment is returned as it is; quotes and escapes are resolved by unquoteTagToken.
+func splitTagSegments(tagValue string) ([]string, error) {
+	segments := make([]string, 0)
+	start := 0
+	inQuote := false
+	for i := 0; i < len(tagValue); i++ {
+		switch tagValue[i] {
+		case '\\':
+			i++ // skip the escaped character
+		case '\'':
+			inQuote = !inQuote
+		case ',':
+			if !inQuote {
+				segments = append(segments, tagValue[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quote in %q: %w", tagValue, ErrMalformedTag)
+	}
+	return append(segments, tagValue[start:]), nil
+}
+
+// indexOfUnquoted returns the index of the first given character that is neither quoted nor escaped. If there is no such character, this returns -1.
+func indexOfUnquoted(s string, c byte) int {
+	inQuote := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '\'':
+			inQuote = !inQuote
+		case c:
+			if !inQuote {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// unquoteTagToken removes the quotes and resolves the escapes in given token.
+func unquoteTagToken(token string) (string, error) {
+	if !strings.ContainsAny(token, `\'`) {
+		return token, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(token); i++ {
+		switch token[i] {
+		case '\\':
+			if i+1 >= len(token) {
+				return "", fmt.Errorf("dangling escape in %q: %w", token, ErrMalformedTag)
+			}
+			i++
+			b.WriteByte(token[i])
+		case '\'':
+			// quotes only group the characters; drop them
+		default:
+			b.WriteByte(token[i])
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTag(t *testing.T) {
+	tag, err := ParseTag("foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", tag.ParamName)
+	assert.Empty(t, tag.Options)
+
+	tag, err = ParseTag(" foo , unixTimeUnit=millisec,timeLayout=2006-01-02 15:04:05 ")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", tag.ParamName)
+	assert.Equal(t, map[string]string{
+		"unixTimeUnit": "millisec",
+		"timeLayout":   "2006-01-02 15:04:05",
+	}, tag.Options)
+
+	tag, err = ParseTag("")
+	assert.NoError(t, err)
+	assert.Equal(t, "", tag.ParamName)
+
+	tag, err = ParseTag("foo,")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", tag.ParamName)
+	assert.Empty(t, tag.Options)
+}
+
+func TestParseTag_WithQuotedValue(t *testing.T) {
+	tag, err := ParseTag("foo, timeLayout='Mon, 02 Jan 2006 15:04:05 MST', unixTimeUnit=sec")
+	assert.NoError(t, err)
+	timeLayout, ok := tag.Option("timeLayout")
+	assert.True(t, ok)
+	assert.Equal(t, "Mon, 02 Jan 2006 15:04:05 MST", timeLayout)
+	unixTimeUnit, ok := tag.Option("unixTimeUnit")
+	assert.True(t, ok)
+	assert.Equal(t, "sec", unixTimeUnit)
+
+	tag, err = ParseTag("foo, timeLayout=' 2006=01 '")
+	assert.NoError(t, err)
+	timeLayout, _ = tag.Option("timeLayout")
+	assert.Equal(t, " 2006=01 ", timeLayout)
+
+	tag, err = ParseTag(`foo, timeLayout='it\'s'`)
+	assert.NoError(t, err)
+	timeLayout, _ = tag.Option("timeLayout")
+	assert.Equal(t, "it's", timeLayout)
+}
+
+func TestParseTag_WithEscapedValue(t *testing.T) {
+	tag, err := ParseTag(`foo\,bar, timeLayout=Mon\, 02 Jan 2006`)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo,bar", tag.ParamName)
+	timeLayout, _ := tag.Option("timeLayout")
+	assert.Equal(t, "Mon, 02 Jan 2006", timeLayout)
+}
+
+func TestParseTag_ShouldRaiseErrorWhenMalformed(t *testing.T) {
+	_, err := ParseTag("foo, timeLayout='Mon, 02 Jan")
+	assert.ErrorIs(t, err, ErrMalformedTag)
+
+	_, err = ParseTag(`foo, timeLayout=2006\`)
+	assert.ErrorIs(t, err, ErrMalformedTag)
+}
+
+func TestParseTag_ShouldRaiseErrorWithUnknownOption(t *testing.T) {
+	_, err := ParseTag("foo, unknown=value")
+	assert.ErrorIs(t, err, ErrUnknownTagOption)
+
+	// the remnant of a comma-separated layout must not be accepted silently
+	_, err = ParseTag("foo, timeLayout=Mon, 02 Jan 2006 15:04:05 MST")
+	assert.ErrorIs(t, err, ErrUnknownTagOption)
+}
+
+func TestParseTag_ShouldRaiseErrorWithDuplicatedOption(t *testing.T) {
+	_, err := ParseTag("foo, unixTimeUnit=sec, unixTimeUnit=millisec")
+	assert.ErrorIs(t, err, ErrDuplicatedTagOption)
+}
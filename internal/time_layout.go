@@ -0,0 +1,36 @@
+package internal
+
+import "time"
+
+// namedTimeLayouts is the layouts that can be referred by the names of the constants of `time` package.
+var namedTimeLayouts = map[string]string{
+	"Layout":      time.Layout,
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+	// the following ones are added in Go 1.20
+	"DateTime": "2006-01-02 15:04:05",
+	"DateOnly": "2006-01-02",
+	"TimeOnly": "15:04:05",
+}
+
+// ResolveTimeLayout returns the layout of given value of `timeLayout` option.
+// If the value is the name of a layout constant of `time` package (e.g. `RFC3339`), this returns that layout; otherwise this returns the value as it is.
+func ResolveTimeLayout(timeLayout string) string {
+	if layout, ok := namedTimeLayouts[timeLayout]; ok {
+		return layout
+	}
+	return timeLayout
+}
@@ -0,0 +1,17 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveTimeLayout(t *testing.T) {
+	assert.Equal(t, time.RFC3339, ResolveTimeLayout("RFC3339"))
+	assert.Equal(t, time.RFC3339Nano, ResolveTimeLayout("RFC3339Nano"))
+	assert.Equal(t, time.Kitchen, ResolveTimeLayout("Kitchen"))
+	assert.Equal(t, "2006-01-02", ResolveTimeLayout("DateOnly"))
+	assert.Equal(t, "2006/01/02", ResolveTimeLayout("2006/01/02"))
+	assert.Equal(t, "", ResolveTimeLayout(""))
+}
@@ -0,0 +1,29 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	_ "time/tzdata" // embeds the zone data, so that the zones can be loaded on the systems that don't have that
+)
+
+var ErrUnknownTimeZone = errors.New("unknown time zone has given")
+
+// LoadTimeZone returns the location of given value of `timeZone` option; `UTC`, `Local`, or an IANA time zone name (e.g. `Asia/Tokyo`).
+// An empty value returns nil, which means the time is used in its own location.
+func LoadTimeZone(timeZone string) (*time.Location, error) {
+	switch timeZone {
+	case "":
+		return nil, nil
+	case "UTC":
+		return time.UTC, nil
+	case "Local":
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("%s is unknown (%s): %w", timeZone, err, ErrUnknownTimeZone)
+	}
+	return loc, nil
+}
@@ -0,0 +1,30 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadTimeZone(t *testing.T) {
+	loc, err := LoadTimeZone("")
+	assert.NoError(t, err)
+	assert.Nil(t, loc)
+
+	loc, err = LoadTimeZone("UTC")
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, loc)
+
+	loc, err = LoadTimeZone("Local")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Local, loc)
+
+	loc, err = LoadTimeZone("Asia/Tokyo")
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", loc.String())
+
+	_, err = LoadTimeZone("Mars/Olympus_Mons")
+	assert.ErrorIs(t, err, ErrUnknownTimeZone)
+	assert.Contains(t, err.Error(), "Mars/Olympus_Mons")
+}
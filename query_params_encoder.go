@@ -0,0 +1,41 @@
+package taqc
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// EncodeQueryParams encodes given query parameters into the URL-encoded form (e.g. `bar=baz&foo=1&verbose`).
+//
+// This is the same as `url.Values.Encode()`, i.e. the parameters are sorted by the names, except a blank value is rendered as a bare flag;
+// e.g. `verbose` instead of `verbose=`. That is the form of `boolFormat=presence` option, which `url.Values.Encode()` cannot express.
+// Note that the other blank values (e.g. the one of `keepEmpty` option) are rendered as the bare flags as well; `url.ParseQuery()` parses both forms equally.
+func EncodeQueryParams(qp url.Values) string {
+	if qp == nil {
+		return ""
+	}
+
+	keys := make([]string, 0, len(qp))
+	for k := range qp {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf strings.Builder
+	for _, k := range keys {
+		keyEscaped := url.QueryEscape(k)
+		for _, v := range qp[k] {
+			if buf.Len() > 0 {
+				buf.WriteByte('&')
+			}
+			buf.WriteString(keyEscaped)
+			if v == "" {
+				continue
+			}
+			buf.WriteByte('=')
+			buf.WriteString(url.QueryEscape(v))
+		}
+	}
+	return buf.String()
+}
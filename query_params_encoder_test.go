@@ -0,0 +1,34 @@
+package taqc
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeQueryParams(t *testing.T) {
+	assert.Equal(t, "", EncodeQueryParams(nil))
+	assert.Equal(t, "", EncodeQueryParams(url.Values{}))
+	assert.Equal(t, "a%26b=c+d&foo=1&foo=2&verbose", EncodeQueryParams(url.Values{
+		"verbose": []string{""},
+		"foo":     []string{"1", "2"},
+		"a&b":     []string{"c d"},
+	}))
+
+	qp, err := url.ParseQuery("verbose&foo=1")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo=1&verbose", EncodeQueryParams(qp))
+}
+
+func TestEncodeQueryParams_WithPresenceBoolFormat(t *testing.T) {
+	type Query struct {
+		Verbose bool   `taqc:"verbose, boolFormat=presence"`
+		Debug   bool   `taqc:"debug, boolFormat=presence"`
+		Q       string `taqc:"q"`
+	}
+
+	qp, err := ConvertToQueryParams(&Query{Verbose: true, Q: "foo"})
+	assert.NoError(t, err)
+	assert.Equal(t, "q=foo&verbose", EncodeQueryParams(qp))
+}
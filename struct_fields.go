@@ -0,0 +1,326 @@
+package taqc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/moznion/taqc/internal"
+)
+
+// structField is a field of the structure that has `taqc` tag, or an embedded structure whose fields are promoted.
+type structField struct {
+	index int
+	typ   reflect.Type
+	// parsedTag is the tag of the field. This is nil for an embedded structure that has no tag.
+	parsedTag *internal.Tag
+	// paramName is the actual query parameter name of the field.
+	paramName string
+	// path is the path of the field from the top-level structure (e.g. `Filter.Status`); that contains the placeholders of the indices of the slices of struct.
+	path string
+	// depth is the depth of the embedded structure that has the field; it is 0 for the fields of the top-level structure.
+	depth int
+	// shadowed reports whether the parameter is shadowed by the field that has the same name at a shallower depth.
+	shadowed bool
+
+	// nestedType is the structure type when the field has `inline` option or the field is an embedded structure; otherwise nil.
+	nestedType reflect.Type
+	// isPtr reports whether the field is a pointer of the nestedType.
+	isPtr bool
+	// nested is the fields of the nestedType.
+	nested []*structField
+
+	// elemType is the structure type of the elements when the field is a slice of struct that has `inline` option; otherwise nil.
+	elemType reflect.Type
+	// isElemPtr reports whether the element is a pointer of the elemType.
+	isElemPtr bool
+	// elemFields is the fields of the elemType. Their parameter names contain the placeholder of the index of the element.
+	elemFields []*structField
+	// elemPattern is the pattern of the parameter names of the elements.
+	elemPattern internal.ParamPattern
+
+	// mapPrefix and mapSuffix are the affixes of the parameter names of the map field; each parameter name is `mapPrefix + key + mapSuffix`.
+	mapPrefix string
+	mapSuffix string
+	// mapCatchAll reports whether the map field takes the parameters of any name in the structure (see internal.IsCatchAllMap).
+	mapCatchAll bool
+	// reservedPatterns is the patterns of the parameter names of the other fields, and reservedAffixes is the affixes of the other map fields.
+	// The catch-all map field doesn't take the parameters that match them on decoding.
+	reservedPatterns []internal.ParamPattern
+	reservedAffixes  []mapAffixes
+
+	// collectionFormat, keepEmpty, and parsers are the decoding options of the field that are parsed from the tag once (see parseDecodingOptions()).
+	// collectionFormatErr and parsersErr are the errors of parsing them; they are returned on decoding the field, where the options are used.
+	collectionFormat    internal.CollectionFormat
+	collectionFormatErr error
+	keepEmpty           bool
+	parsers             *valueParsers
+	parsersErr          error
+}
+
+// mapAffixes is the affixes of the parameter names of a map field.
+type mapAffixes struct {
+	prefix string
+	suffix string
+}
+
+// matches returns whether given parameter name has the affixes. The placeholders of the indices in the affixes are filled with given indices.
+func (a mapAffixes) matches(paramName string, indices []int) bool {
+	prefix, suffix := internal.FillIndices(a.prefix, indices), internal.FillIndices(a.suffix, indices)
+	return len(paramName) >= len(prefix)+len(suffix) && strings.HasPrefix(paramName, prefix) && strings.HasSuffix(paramName, suffix)
+}
+
+var structFieldsCache sync.Map // map[reflect.Type][]*structField
+
+// getStructFields returns the fields of given structure type that are relevant to the query parameters.
+// This is built once per type and cached.
+func getStructFields(t reflect.Type) ([]*structField, error) {
+	if fields, ok := structFieldsCache.Load(t); ok {
+		return fields.([]*structField), nil
+	}
+
+	fields, err := collectStructFields(t, internal.IdentityParamNamer, "", 0, []reflect.Type{t})
+	if err != nil {
+		return nil, maskFieldErrorIndices(err)
+	}
+	err = resolveShadowedFields(fields)
+	if err != nil {
+		return nil, maskFieldErrorIndices(err)
+	}
+
+	actual, _ := structFieldsCache.LoadOrStore(t, fields)
+	return actual.([]*structField), nil
+}
+
+// collectStructFields collects the tagged fields of given structure type, and the fields of the nested and the embedded structures recursively.
+// pathPrefix is the path of the structure from the top-level one, and visiting is the structure types that are being collected; it is used to detect a cycle of the nested structures.
+// This collects the errors of all the fields, and returns them as FieldErrors.
+func collectStructFields(t reflect.Type, namer internal.ParamNamer, pathPrefix string, depth int, visiting []reflect.Type) ([]*structField, error) {
+	fields := make([]*structField, 0, t.NumField())
+	var errs FieldErrors
+	for i := 0; i < t.NumField(); i++ {
+		field, err := collectStructField(t.Field(i), i, namer, pathPrefix, depth, visiting)
+		if err != nil {
+			errs = appendFieldErrors(errs, field, err)
+			continue
+		}
+		if field != nil {
+			fields = append(fields, field)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return fields, nil
+}
+
+// collectStructField collects given field of the structure. If the field is irrelevant to the query parameters, this returns nil.
+// On error, this returns the field as well to describe the error; that has the path, the type, and the parameter name if possible.
+func collectStructField(typeField reflect.StructField, index int, namer internal.ParamNamer, pathPrefix string, depth int, visiting []reflect.Type) (*structField, error) {
+	field := &structField{
+		index: index,
+		typ:   typeField.Type,
+		path:  pathPrefix + typeField.Name,
+		depth: depth,
+	}
+
+	tagValue, ok := typeField.Tag.Lookup(internal.TagName)
+	if !ok {
+		if !typeField.Anonymous {
+			return nil, nil
+		}
+
+		// promote the fields of the embedded structure
+		structType, isPtr := indirectStructType(typeField.Type)
+		if structType == nil {
+			return nil, nil
+		}
+		err := checkCyclicStruct(structType, visiting)
+		if err != nil {
+			return field, err
+		}
+		nested, err := collectStructFields(structType, namer, field.path+".", depth+1, append(visiting[:len(visiting):len(visiting)], structType))
+		if err != nil {
+			return field, err
+		}
+		field.nestedType = structType
+		field.isPtr = isPtr
+		field.nested = nested
+		return field, nil
+	}
+
+	parsedTag, err := internal.ParseTag(tagValue)
+	if err != nil {
+		return field, err
+	}
+	paramName := parsedTag.ParamName
+	if paramName != "" {
+		// the parameter name describes the errors below as well
+		field.paramName = namer(paramName)
+	}
+	if typeField.PkgPath != "" {
+		return field, ErrUnexportedField
+	}
+	if paramName == "" {
+		return field, ErrQueryParameterNameIsEmpty
+	}
+	field.parsedTag = parsedTag
+
+	if _, inline := parsedTag.Option("inline"); inline && typeField.Type.Kind() == reflect.Slice {
+		elemType, isElemPtr := indirectStructType(typeField.Type.Elem())
+		if elemType == nil {
+			return field, fmt.Errorf("inline field type is %s: %w", typeField.Type, ErrUnsupportedFieldType)
+		}
+		err := checkCyclicStruct(elemType, visiting)
+		if err != nil {
+			return field, err
+		}
+		elemNamer, err := internal.IndexedParamNamer(parsedTag, namer)
+		if err != nil {
+			return field, err
+		}
+		elemFields, err := collectStructFields(elemType, elemNamer, field.path+"["+internal.IndexPlaceholder+"].", 0, append(visiting[:len(visiting):len(visiting)], elemType))
+		if err != nil {
+			return field, err
+		}
+		err = resolveShadowedFields(elemFields)
+		if err != nil {
+			return field, err
+		}
+		field.elemType = elemType
+		field.isElemPtr = isElemPtr
+		field.elemFields = elemFields
+		field.elemPattern = internal.ElemParamPattern(elemNamer)
+	} else if inline {
+		structType, isPtr := indirectStructType(typeField.Type)
+		if structType == nil {
+			return field, fmt.Errorf("inline field type is %s: %w", typeField.Type, ErrUnsupportedFieldType)
+		}
+		err := checkCyclicStruct(structType, visiting)
+		if err != nil {
+			return field, err
+		}
+		nestedNamer, err := internal.NestedParamNamer(parsedTag, namer)
+		if err != nil {
+			return field, err
+		}
+		nested, err := collectStructFields(structType, nestedNamer, field.path+".", depth, append(visiting[:len(visiting):len(visiting)], structType))
+		if err != nil {
+			return field, err
+		}
+		field.nestedType = structType
+		field.isPtr = isPtr
+		field.nested = nested
+	} else if typeField.Type.Kind() == reflect.Map {
+		field.mapPrefix, field.mapSuffix, err = internal.MapParamAffixes(parsedTag, namer)
+		if err != nil {
+			return field, err
+		}
+		field.mapCatchAll = internal.IsCatchAllMap(parsedTag)
+	}
+	if field.nestedType == nil && field.elemType == nil {
+		field.parseDecodingOptions()
+	}
+
+	return field, nil
+}
+
+// resolveShadowedFields marks the fields that are shadowed by the fields at shallower depths.
+// The map fields are out of this, since the names of their parameters are dynamic.
+func resolveShadowedFields(fields []*structField) error {
+	leaves := make([]*structField, 0)
+	mapFields := make([]*structField, 0)
+	var collectLeaves func(fields []*structField)
+	collectLeaves = func(fields []*structField) {
+		for _, field := range fields {
+			if field.nestedType != nil {
+				collectLeaves(field.nested)
+				continue
+			}
+			if field.typ.Kind() == reflect.Map {
+				mapFields = append(mapFields, field)
+				continue
+			}
+			leaves = append(leaves, field)
+		}
+	}
+	collectLeaves(fields)
+
+	entries := make([]internal.ParamEntry, len(leaves))
+	for i, leaf := range leaves {
+		entries[i] = internal.ParamEntry{
+			Name:  leaf.paramName,
+			Depth: leaf.depth,
+		}
+	}
+	shadowed, err := internal.ResolveShadowedParams(entries)
+	if err != nil {
+		return ambiguousFieldErrors(leaves, entries)
+	}
+	reservedPatterns := make([]internal.ParamPattern, 0, len(leaves))
+	for i, leaf := range leaves {
+		leaf.shadowed = shadowed[i]
+		reservedPatterns = append(reservedPatterns, leaf.paramPatterns()...)
+	}
+	reservedAffixes := make([]mapAffixes, 0, len(mapFields))
+	for _, mapField := range mapFields {
+		if !mapField.mapCatchAll {
+			reservedAffixes = append(reservedAffixes, mapAffixes{prefix: mapField.mapPrefix, suffix: mapField.mapSuffix})
+		}
+	}
+	for _, mapField := range mapFields {
+		mapField.reservedPatterns = reservedPatterns
+		mapField.reservedAffixes = reservedAffixes
+	}
+	return nil
+}
+
+// paramPatterns returns the patterns of the parameter names that the field produces; the ones of the elements for the slice of struct field,
+// and the ones according to the collection format for the other fields.
+func (f *structField) paramPatterns() []internal.ParamPattern {
+	if f.elemType != nil {
+		return []internal.ParamPattern{f.elemPattern}
+	}
+	// the invalid collection format is reported on encoding and decoding
+	return internal.FieldParamPatterns(f.paramName, f.collectionFormat)
+}
+
+// ambiguousFieldErrors returns the errors of the fields that have the same parameter name as a preceding field at the shallowest depth;
+// each error names the preceding field as well.
+func ambiguousFieldErrors(leaves []*structField, entries []internal.ParamEntry) FieldErrors {
+	var errs FieldErrors
+	for _, i := range internal.FindAmbiguousParams(entries) {
+		for j := 0; j < i; j++ {
+			if entries[j] == entries[i] {
+				errs = append(errs, newFieldError(leaves[i], fmt.Errorf("%q is duplicated with the field %s: %w", leaves[i].paramName, leaves[j].path, ErrAmbiguousQueryParameter)))
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// indirectStructType returns the structure type of given struct or pointer of struct type, and whether that is a pointer.
+// If the type is neither of them, this returns nil.
+func indirectStructType(t reflect.Type) (reflect.Type, bool) {
+	isPtr := t.Kind() == reflect.Ptr
+	if isPtr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+	return t, isPtr
+}
+
+// checkCyclicStruct returns an error when given structure type is in visiting.
+func checkCyclicStruct(t reflect.Type, visiting []reflect.Type) error {
+	for _, v := range visiting {
+		if v == t {
+			return fmt.Errorf("%s: %w", t, ErrCyclicNestedStruct)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package taqc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TimeFormatterFunc formats `time.Time` to the query parameter value.
+type TimeFormatterFunc func(t time.Time) string
+
+// TimeParserFunc parses the query parameter value to `time.Time`; this is the reverse operation of TimeFormatterFunc.
+type TimeParserFunc func(s string) (time.Time, error)
+
+// namedTimeFormatter is the pair of the formatter and the parser that are registered by RegisterTimeFormatter.
+type namedTimeFormatter struct {
+	formatter TimeFormatterFunc
+	parser    TimeParserFunc
+}
+
+var timeFormatters sync.Map // map[string]*namedTimeFormatter
+
+// RegisterTimeFormatter registers the formatter and the parser of `time.Time` by given name, which `timeFormatter` option of the tag refers to.
+// `ConvertToQueryParams()` uses the formatter, and `UnmarshalQueryParams()` uses the parser; the parser can be nil if the decoding is unnecessary.
+//
+// This should be called before the conversions (e.g. in `init()`); registering a formatter discards the compiled encoding plans,
+// and the conversions that start after the registration use the formatter even if the other conversions run concurrently.
+func RegisterTimeFormatter(name string, formatter TimeFormatterFunc, parser TimeParserFunc) {
+	updateRegistry(func() {
+		timeFormatters.Store(name, &namedTimeFormatter{
+			formatter: formatter,
+			parser:    parser,
+		})
+	})
+}
+
+// lookupTimeFormatter returns the formatter and the parser that are registered by given name.
+func lookupTimeFormatter(name string) (*namedTimeFormatter, error) {
+	f, ok := timeFormatters.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, ErrUnknownTimeFormatter)
+	}
+	return f.(*namedTimeFormatter), nil
+}
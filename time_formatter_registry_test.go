@@ -0,0 +1,125 @@
+package taqc
+
+import (
+	"math"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func formatUnixFrac(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/float64(time.Second), 'f', 3, 64)
+}
+
+func parseUnixFrac(s string) (time.Time, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(math.Round(frac*1000))*int64(time.Millisecond)), nil
+}
+
+func init() {
+	RegisterTimeFormatter("unixFrac", formatUnixFrac, parseUnixFrac)
+	RegisterTimeFormatter("formatOnly", func(t time.Time) string {
+		return t.Format("20060102")
+	}, nil)
+}
+
+func TestConvertToQueryParams_WithNamedTimeLayout(t *testing.T) {
+	type Query struct {
+		RFC3339  time.Time  `taqc:"rfc3339, timeLayout=RFC3339"`
+		DateOnly *time.Time `taqc:"date_only, timeLayout=DateOnly"`
+		Kitchen  time.Time  `taqc:"kitchen, timeLayout=Kitchen"`
+	}
+
+	ts := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	qp, err := ConvertToQueryParams(&Query{
+		RFC3339:  ts,
+		DateOnly: &ts,
+		Kitchen:  ts,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"rfc3339":   []string{"2023-11-14T22:13:20Z"},
+		"date_only": []string{"2023-11-14"},
+		"kitchen":   []string{"10:13PM"},
+	}, qp)
+
+	var q Query
+	err = UnmarshalQueryParams(qp, &q)
+	assert.NoError(t, err)
+	assert.True(t, ts.Equal(q.RFC3339))
+	assert.Equal(t, time.Date(2023, 11, 14, 0, 0, 0, 0, time.UTC), *q.DateOnly)
+}
+
+func TestConvertToQueryParams_WithRegisteredTimeFormatter(t *testing.T) {
+	type Query struct {
+		Frac        time.Time   `taqc:"frac, timeFormatter=unixFrac"`
+		Fracs       []time.Time `taqc:"fracs, timeFormatter=unixFrac"`
+		Prioritized time.Time   `taqc:"prioritized, timeLayout=RFC3339, timeFormatter=formatOnly, timeZone=Asia/Tokyo"`
+	}
+
+	ts := time.Unix(1700000000, 123*int64(time.Millisecond))
+	qp, err := ConvertToQueryParams(&Query{
+		Frac:        ts,
+		Fracs:       []time.Time{ts, ts.Add(time.Second)},
+		Prioritized: time.Date(2023, 11, 14, 22, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"frac":        []string{"1700000000.123"},
+		"fracs":       []string{"1700000000.123", "1700000001.123"},
+		"prioritized": []string{"20231115"},
+	}, qp)
+
+	type DecodingQuery struct {
+		Frac  time.Time   `taqc:"frac, timeFormatter=unixFrac"`
+		Fracs []time.Time `taqc:"fracs, timeFormatter=unixFrac"`
+	}
+	var q DecodingQuery
+	err = UnmarshalQueryParams(qp, &q)
+	assert.NoError(t, err)
+	assert.True(t, ts.Equal(q.Frac))
+	assert.Len(t, q.Fracs, 2)
+	assert.True(t, ts.Add(time.Second).Equal(q.Fracs[1]))
+}
+
+func TestConvertToQueryParams_ShouldRaiseErrorWhenTimeFormatterIsUnknown(t *testing.T) {
+	type Query struct {
+		Foo time.Time `taqc:"foo, timeFormatter=unknown"`
+	}
+	_, err := ConvertToQueryParams(&Query{})
+	assert.ErrorIs(t, err, ErrUnknownTimeFormatter)
+
+	err = UnmarshalQueryParams(url.Values{"foo": []string{"1"}}, &Query{})
+	assert.ErrorIs(t, err, ErrUnknownTimeFormatter)
+}
+
+func TestUnmarshalQueryParams_ShouldUseTimeFormatterRegisteredAfterCachingFields(t *testing.T) {
+	type Query struct {
+		Foo time.Time `taqc:"foo, timeFormatter=lateUnixFrac"`
+	}
+	qp := url.Values{"foo": []string{"1700000000.500"}}
+	err := UnmarshalQueryParams(qp, &Query{})
+	assert.ErrorIs(t, err, ErrUnknownTimeFormatter)
+
+	RegisterTimeFormatter("lateUnixFrac", formatUnixFrac, parseUnixFrac)
+	var q Query
+	err = UnmarshalQueryParams(qp, &q)
+	assert.NoError(t, err)
+	assert.True(t, time.Unix(1700000000, 500*int64(time.Millisecond)).Equal(q.Foo))
+}
+
+func TestUnmarshalQueryParams_ShouldRaiseErrorWhenTimeFormatterHasNoParser(t *testing.T) {
+	type Query struct {
+		Foo time.Time `taqc:"foo, timeFormatter=formatOnly"`
+	}
+	err := UnmarshalQueryParams(url.Values{"foo": []string{"20231115"}}, &Query{})
+	assert.ErrorIs(t, err, ErrUnknownTimeFormatter)
+	assert.Contains(t, err.Error(), "has no parser")
+}
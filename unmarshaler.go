@@ -0,0 +1,549 @@
+package taqc
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moznion/taqc/internal"
+)
+
+var (
+	ErrNonPointerValueGiven       = errors.New("given value is not a pointer of the structure")
+	ErrInvalidQueryParameterValue = errors.New("invalid query parameter value has come")
+)
+
+// UnmarshalQueryParams populates given structure from the query parameters according to the custom tags.
+//
+// This is the reverse operation of `ConvertToQueryParams()`; it reads the same `taqc` tags and supports the same field types.
+// `v` must be a non-nil pointer of the structure.
+//
+// When a query parameter that corresponds to a field is absent, it leaves that field as it is.
+// For `bool` fields, the value `1` becomes `true` and any other value becomes `false`. `boolFormat` option changes that in the same manner as `ConvertToQueryParams()`;
+// `numeric`, `truefalse`, and `yesno` accept only the values of that format, and `presence` makes `true` when the parameter is present (e.g. `?verbose`).
+// For pointer fields, it allocates a new value and sets the pointer to that.
+// For slice fields, it uses all values of the query parameter; otherwise, it uses only the first value.
+// If the slice field has `keepEmpty` option, a single blank value (i.e. `param_name=`) becomes a zero-length slice.
+// A slice field is decoded according to `collectionFormat` option in the same manner as `ConvertToQueryParams()` encodes that.
+// For `indexed`, it takes the values from index 0 until the index is missing.
+//
+// `time.Time` fields are parsed according to `timeLayout` and `unixTimeUnit` custom tag values, in the same manner as `ConvertToQueryParams()`.
+// When `timeZone` option is given, a timestamp without offset is parsed in that location, and the parsed time is converted into that location.
+// `time.Duration` fields are parsed according to `durationUnit` and `durationFormat` custom tag values as well.
+// A field whose type (or the pointer of that) implements `encoding.TextUnmarshaler` is decoded by `UnmarshalText()`, except the types of `time` package.
+// A field whose type is encoded by `MarshalText()` or `String()` but doesn't implement `encoding.TextUnmarshaler` cannot be decoded (`ErrUnsupportedFieldType`).
+//
+// The fields that have `inline` option are populated recursively from the nested parameters.
+// For a pointer of struct, it allocates a new value only when any nested parameter is present.
+// A slice of struct field that has `inline` option takes the elements from index 0 until no parameter of the element is present.
+//
+// A map field takes the parameters whose names match that field (e.g. `param_name[key]` for deepObject style); the value of each entry is
+// the first value of the parameter, or all the values when the map value is a slice. A map field that has `mapStyle=flat` option and no `prefix`
+// takes all the parameters except the ones of the other non-map fields.
+// The fields of the embedded structs are populated in the same manner as `ConvertToQueryParams()` promotes them.
+func UnmarshalQueryParams(qp url.Values, v interface{}) error {
+	if v == nil {
+		return ErrNilValueGiven
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrNonPointerValueGiven
+	}
+
+	fields, err := getStructFields(rv.Elem().Type())
+	if err != nil {
+		return firstError(err)
+	}
+
+	_, err = unmarshalStruct(qp, rv.Elem(), fields, nil)
+	return err
+}
+
+// unmarshalStruct populates given fields of the structure value. This returns whether any field is populated.
+// indices is the indices of the elements of the enclosing slices of struct, which fill the placeholders in the parameter names.
+func unmarshalStruct(qp url.Values, elem reflect.Value, fields []*structField, indices []int) (bool, error) {
+	populated := false
+	for _, structField := range fields {
+		if structField.nestedType != nil {
+			nestedPopulated, err := unmarshalNestedStruct(qp, elem.Field(structField.index), structField, indices)
+			if err != nil {
+				return false, err
+			}
+			populated = populated || nestedPopulated
+			continue
+		}
+		if structField.shadowed {
+			continue
+		}
+
+		if structField.elemType != nil {
+			slicePopulated, err := unmarshalSliceOfStruct(qp, elem.Field(structField.index), structField, indices)
+			if err != nil {
+				return false, err
+			}
+			populated = populated || slicePopulated
+			continue
+		}
+
+		if structField.typ.Kind() == reflect.Map {
+			mapPopulated, err := unmarshalMap(qp, elem.Field(structField.index), structField, indices)
+			if err != nil {
+				return false, err
+			}
+			populated = populated || mapPopulated
+			continue
+		}
+
+		paramName := internal.FillIndices(structField.paramName, indices)
+
+		values := qp[paramName]
+		if structField.typ.Kind() == reflect.Slice {
+			if structField.collectionFormatErr != nil {
+				return false, structField.collectionFormatErr
+			}
+			var err error
+			values, err = getCollectionValues(qp, paramName, structField.collectionFormat)
+			if err != nil {
+				return false, err
+			}
+		}
+		if len(values) <= 0 {
+			continue
+		}
+		populated = true
+
+		parsers, err := structField.getValueParsers()
+		if err != nil {
+			return false, err
+		}
+
+		field := elem.Field(structField.index)
+		fieldKind := field.Kind()
+		switch fieldKind {
+		case reflect.Ptr:
+			ptr := reflect.New(field.Type().Elem())
+			err := setQueryParamValue(ptr.Elem(), values[0], parsers)
+			if err != nil {
+				return false, fmt.Errorf("parameter %s: %w", paramName, err)
+			}
+			field.Set(ptr)
+		case reflect.Slice:
+			if field.Type().Elem().Kind() == reflect.Bool && !parsers.boolFormat.SupportsCollection() {
+				return false, fmt.Errorf("%s for []bool: %w", parsers.boolFormat, ErrUnsupportedBoolFormat)
+			}
+			if structField.keepEmpty && len(values) == 1 && values[0] == "" {
+				field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+				continue
+			}
+			slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+			for j, value := range values {
+				err := setQueryParamValue(slice.Index(j), value, parsers)
+				if err != nil {
+					return false, fmt.Errorf("parameter %s: %w", paramName, err)
+				}
+			}
+			field.Set(slice)
+		default:
+			err := setQueryParamValue(field, values[0], parsers)
+			if err != nil {
+				return false, fmt.Errorf("parameter %s: %w", paramName, err)
+			}
+		}
+	}
+
+	return populated, nil
+}
+
+// unmarshalNestedStruct populates the field that has `inline` option or the embedded structure. This returns whether any nested field is populated.
+// If the field is a pointer of struct, it allocates a new value only when any nested field is populated.
+func unmarshalNestedStruct(qp url.Values, field reflect.Value, structField *structField, indices []int) (bool, error) {
+	if !structField.isPtr {
+		return unmarshalStruct(qp, field, structField.nested, indices)
+	}
+
+	ptr := field
+	if field.IsNil() {
+		ptr = reflect.New(structField.nestedType)
+	}
+	populated, err := unmarshalStruct(qp, ptr.Elem(), structField.nested, indices)
+	if err != nil {
+		return false, err
+	}
+	if populated && field.IsNil() {
+		if !field.CanSet() {
+			return false, fmt.Errorf("cannot allocate the embedded pointer of unexported struct %s: %w", structField.nestedType, ErrUnsupportedFieldType)
+		}
+		field.Set(ptr)
+	}
+	return populated, nil
+}
+
+// unmarshalSliceOfStruct populates the slice of struct field that has `inline` option. This returns whether any element is populated.
+// It decodes the elements from index 0 until no parameter of the element is present, and sets the slice only when any element is populated.
+func unmarshalSliceOfStruct(qp url.Values, field reflect.Value, structField *structField, indices []int) (bool, error) {
+	slice := reflect.MakeSlice(field.Type(), 0, 0)
+	for j := 0; ; j++ {
+		ptr := reflect.New(structField.elemType)
+		populated, err := unmarshalStruct(qp, ptr.Elem(), structField.elemFields, append(indices[:len(indices):len(indices)], j))
+		if err != nil {
+			return false, err
+		}
+		if !populated {
+			break
+		}
+		if structField.isElemPtr {
+			slice = reflect.Append(slice, ptr)
+		} else {
+			slice = reflect.Append(slice, ptr.Elem())
+		}
+	}
+
+	if slice.Len() <= 0 {
+		return false, nil
+	}
+	field.Set(slice)
+	return true, nil
+}
+
+// unmarshalMap populates the map field from the parameters whose names match the affixes of that. This returns whether any entry is populated.
+func unmarshalMap(qp url.Values, field reflect.Value, structField *structField, indices []int) (bool, error) {
+	mapType := field.Type()
+	valueType := mapType.Elem()
+	if mapType.Key().Kind() != reflect.String {
+		return false, fmt.Errorf("%s is unsupported: %w", mapType, ErrUnsupportedFieldType)
+	}
+
+	prefix, suffix := internal.FillIndices(structField.mapPrefix, indices), internal.FillIndices(structField.mapSuffix, indices)
+	var parsers *valueParsers
+	populated := false
+	for paramName, values := range qp {
+		if len(values) <= 0 || len(paramName) < len(prefix)+len(suffix) || !strings.HasPrefix(paramName, prefix) || !strings.HasSuffix(paramName, suffix) {
+			continue
+		}
+		if structField.mapCatchAll && isReservedParamName(paramName, structField, indices) {
+			continue
+		}
+
+		if parsers == nil {
+			var err error
+			parsers, err = structField.getValueParsers()
+			if err != nil {
+				return false, err
+			}
+		}
+
+		value := reflect.New(valueType).Elem()
+		if valueType.Kind() == reflect.Slice {
+			if valueType.Elem().Kind() == reflect.Bool && !parsers.boolFormat.SupportsCollection() {
+				return false, fmt.Errorf("%s for %s: %w", parsers.boolFormat, valueType, ErrUnsupportedBoolFormat)
+			}
+			value.Set(reflect.MakeSlice(valueType, len(values), len(values)))
+			for j, v := range values {
+				err := setQueryParamValue(value.Index(j), v, parsers)
+				if err != nil {
+					return false, fmt.Errorf("parameter %s: %w", paramName, err)
+				}
+			}
+		} else {
+			err := setQueryParamValue(value, values[0], parsers)
+			if err != nil {
+				return false, fmt.Errorf("parameter %s: %w", paramName, err)
+			}
+		}
+
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(mapType))
+		}
+		key := paramName[len(prefix) : len(paramName)-len(suffix)]
+		field.SetMapIndex(reflect.ValueOf(key).Convert(mapType.Key()), value)
+		populated = true
+	}
+
+	return populated, nil
+}
+
+// isReservedParamName returns whether given parameter name belongs to the other fields than the map field, i.e. the parameter of another field
+// or the one that matches the affixes of another map field.
+func isReservedParamName(paramName string, structField *structField, indices []int) bool {
+	for _, pattern := range structField.reservedPatterns {
+		if pattern.Matches(paramName, indices) {
+			return true
+		}
+	}
+	for _, affixes := range structField.reservedAffixes {
+		if affixes.matches(paramName, indices) {
+			return true
+		}
+	}
+	return false
+}
+
+// getCollectionValues returns the values of the slice field from the parameters according to given collection format.
+// When the parameters represent a blank value for `keepEmpty` option, this returns a single blank value.
+func getCollectionValues(qp url.Values, paramName string, collectionFormat internal.CollectionFormat) ([]string, error) {
+	switch collectionFormat {
+	case internal.CollectionFormatBrackets:
+		return qp[paramName+"[]"], nil
+	case internal.CollectionFormatIndexed:
+		values := make([]string, 0)
+		for j := 0; ; j++ {
+			vs, ok := qp[paramName+"["+strconv.Itoa(j)+"]"]
+			if !ok || len(vs) <= 0 {
+				break
+			}
+			values = append(values, vs[0])
+		}
+		if vs := qp[paramName]; len(values) <= 0 && len(vs) == 1 && vs[0] == "" {
+			return vs, nil
+		}
+		return values, nil
+	case internal.CollectionFormatCSV, internal.CollectionFormatSSV, internal.CollectionFormatPipes:
+		vs := qp[paramName]
+		if len(vs) <= 0 || vs[0] == "" {
+			return vs, nil
+		}
+		values, err := collectionFormat.SplitCollectionValue(vs[0])
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %s: %w", paramName, err, ErrInvalidQueryParameterValue)
+		}
+		return values, nil
+	default:
+		return qp[paramName], nil
+	}
+}
+
+// parseDecodingOptions parses the options of the tag that the decoding of the field depends on, and caches them into the field.
+func (f *structField) parseDecodingOptions() {
+	collectionFormatValue, _ := f.parsedTag.Option("collectionFormat")
+	f.collectionFormat, f.collectionFormatErr = internal.ParseCollectionFormat(collectionFormatValue)
+	_, f.keepEmpty = f.parsedTag.Option("keepEmpty")
+	f.parsers, f.parsersErr = newValueParsers(f.parsedTag)
+}
+
+// getValueParsers returns the cached parsers of the values of the field.
+func (f *structField) getValueParsers() (*valueParsers, error) {
+	if f.parsersErr != nil {
+		return nil, f.parsersErr
+	}
+	return f.parsers.withTimeFormatter()
+}
+
+// valueParsers is the parsers of the values whose decoding depends on the options of the tag.
+type valueParsers struct {
+	timeParser     func(s string) (time.Time, error)
+	durationParser func(s string) (time.Duration, error)
+	boolFormat     internal.BoolFormat
+
+	// timeFormatter is the name of the time formatter of `timeFormatter` option, and timeZone is the location of `timeZone` option.
+	timeFormatter string
+	timeZone      *time.Location
+}
+
+// newValueParsers returns the parsers according to the options of the tag.
+func newValueParsers(parsedTag *internal.Tag) (*valueParsers, error) {
+	timeFormatter, _ := parsedTag.Option("timeFormatter")
+	timeParser, timeZone, err := newTimeParser(parsedTag)
+	if err != nil {
+		return nil, err
+	}
+	durationParser, err := newDurationParser(parsedTag)
+	if err != nil {
+		return nil, err
+	}
+	boolFormatValue, _ := parsedTag.Option("boolFormat")
+	boolFormat, err := internal.ParseBoolFormat(boolFormatValue)
+	if err != nil {
+		return nil, err
+	}
+	return &valueParsers{
+		timeParser:     timeParser,
+		durationParser: durationParser,
+		boolFormat:     boolFormat,
+		timeFormatter:  timeFormatter,
+		timeZone:       timeZone,
+	}, nil
+}
+
+// withTimeFormatter returns the parsers whose time parser is the parser of the time formatter of `timeFormatter` option.
+// The formatter is looked up on each call, since that can be registered after the parsers are built; without the option, this returns the parsers as they are.
+func (p *valueParsers) withTimeFormatter() (*valueParsers, error) {
+	if p.timeFormatter == "" {
+		return p, nil
+	}
+	f, err := lookupTimeFormatter(p.timeFormatter)
+	if err != nil {
+		return nil, err
+	}
+	if f.parser == nil {
+		return nil, fmt.Errorf("%s has no parser: %w", p.timeFormatter, ErrUnknownTimeFormatter)
+	}
+	loc := p.timeZone
+	parsers := *p
+	parsers.timeParser = func(s string) (time.Time, error) {
+		t, err := f.parser(s)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if loc != nil {
+			return t.In(loc), nil
+		}
+		return t, nil
+	}
+	return &parsers, nil
+}
+
+// newDurationParser returns the parser of `time.Duration` value according to `durationFormat` and `durationUnit` options of the tag.
+func newDurationParser(parsedTag *internal.Tag) (func(s string) (time.Duration, error), error) {
+	durationFormat, _ := parsedTag.Option("durationFormat")
+	durationUnitValue, _ := parsedTag.Option("durationUnit")
+	durationUnit, err := internal.ParseDurationFormat(durationFormat, durationUnitValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if durationUnit == 0 {
+		return time.ParseDuration, nil
+	}
+	return func(s string) (time.Duration, error) {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(i) * durationUnit, nil
+	}, nil
+}
+
+// newTimeParser returns the parser of `time.Time` value according to `timeLayout` and `unixTimeUnit` options of the tag, and the location of `timeZone` option.
+// For `timeFormatter` option, this returns no parser; the one of the formatter is given by valueParsers.withTimeFormatter().
+func newTimeParser(parsedTag *internal.Tag) (func(s string) (time.Time, error), *time.Location, error) {
+	timeLayout, _ := parsedTag.Option("timeLayout")
+	timeLayout = internal.ResolveTimeLayout(timeLayout)
+	unixTimeUnit, _ := parsedTag.Option("unixTimeUnit")
+	timeZone, _ := parsedTag.Option("timeZone")
+
+	unixTimeParser, err := getUnixTimeParser(unixTimeUnit)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc, err := internal.LoadTimeZone(timeZone)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := parsedTag.Option("timeFormatter"); ok { // the highest priority
+		return nil, loc, nil
+	}
+	if timeLayout != "" { // higher priority
+		if loc != nil {
+			return func(s string) (time.Time, error) {
+				t, err := time.ParseInLocation(timeLayout, s, loc)
+				if err != nil {
+					return time.Time{}, err
+				}
+				return t.In(loc), nil
+			}, loc, nil
+		}
+		return func(s string) (time.Time, error) {
+			return time.Parse(timeLayout, s)
+		}, loc, nil
+	}
+	return func(s string) (time.Time, error) {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if loc != nil {
+			return unixTimeParser(i).In(loc), nil
+		}
+		return unixTimeParser(i), nil
+	}, loc, nil
+}
+
+func setQueryParamValue(dst reflect.Value, value string, parsers *valueParsers) error {
+	if dst.Type().PkgPath() != "time" && dst.CanAddr() && dst.Addr().Type().Implements(textUnmarshalerType) {
+		err := dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+		if err != nil {
+			return fmt.Errorf("%s: %w", err, ErrInvalidQueryParameterValue)
+		}
+		return nil
+	}
+	if _, ok := getMarshalerEncoder(dst.Type()); ok {
+		// the value that `MarshalText()` or `String()` encodes cannot be parsed back by the kind of the type
+		return fmt.Errorf("%s has no UnmarshalText() to decode the value that the marshaler encodes: %w", dst.Type(), ErrUnsupportedFieldType)
+	}
+
+	if dst.Type() == durationType {
+		d, err := parsers.durationParser(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", err, ErrInvalidQueryParameterValue)
+		}
+		dst.SetInt(int64(d))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, dst.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s: %w", err, ErrInvalidQueryParameterValue)
+		}
+		dst.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, dst.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s: %w", err, ErrInvalidQueryParameterValue)
+		}
+		dst.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, dst.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s: %w", err, ErrInvalidQueryParameterValue)
+		}
+		dst.SetFloat(f)
+	case reflect.Bool:
+		b, err := parsers.boolFormat.Parse(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", err, ErrInvalidQueryParameterValue)
+		}
+		dst.SetBool(b)
+	case reflect.Struct:
+		if dst.Type().PkgPath() == "time" && dst.Type().Name() == "Time" {
+			t, err := parsers.timeParser(value)
+			if err != nil {
+				return fmt.Errorf("%s: %w", err, ErrInvalidQueryParameterValue)
+			}
+			dst.Set(reflect.ValueOf(t))
+		} else {
+			return fmt.Errorf("%s is unsupported: %w", dst.Type(), ErrUnsupportedFieldType)
+		}
+	default:
+		return fmt.Errorf("%s is unsupported: %w", dst.Type(), ErrUnsupportedFieldType)
+	}
+	return nil
+}
+
+func getUnixTimeParser(unixTimeUnit string) (func(i int64) time.Time, error) {
+	switch unixTimeUnit {
+	case "", "sec":
+		return func(i int64) time.Time {
+			return time.Unix(i, 0)
+		}, nil
+	case "millisec":
+		return time.UnixMilli, nil
+	case "microsec":
+		return time.UnixMicro, nil
+	case "nanosec":
+		return func(i int64) time.Time {
+			return time.Unix(0, i)
+		}, nil
+	default:
+		return nil, fmt.Errorf("%s is unsupported: %w", unixTimeUnit, ErrUnsupportedUnixTimeUnit)
+	}
+}
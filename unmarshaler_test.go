@@ -0,0 +1,731 @@
+package taqc
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/moznion/taqc/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalQueryParams(t *testing.T) {
+	type Query struct {
+		Foo             string  `taqc:"foo"`
+		Bar             int64   `taqc:"bar"`
+		Buz             float64 `taqc:"buz"`
+		Qux             bool    `taqc:"qux"`
+		FooBar          bool    `taqc:"foobar"`
+		ShouldBeIgnored string
+	}
+
+	var q Query
+	err := UnmarshalQueryParams(url.Values{
+		"foo":    []string{"str-value"},
+		"bar":    []string{"123"},
+		"buz":    []string{"456.789000"},
+		"qux":    []string{"1"},
+		"foobar": []string{"0"},
+	}, &q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, Query{
+		Foo:    "str-value",
+		Bar:    123,
+		Buz:    456.789,
+		Qux:    true,
+		FooBar: false,
+	}, q)
+}
+
+func TestUnmarshalQueryParams_ShouldLeaveFieldsWhenParamsAreAbsent(t *testing.T) {
+	type Query struct {
+		Foo string `taqc:"foo"`
+		Bar int64  `taqc:"bar"`
+	}
+
+	q := Query{
+		Foo: "default",
+		Bar: 123,
+	}
+	err := UnmarshalQueryParams(url.Values{
+		"bar": []string{"456"},
+	}, &q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, Query{
+		Foo: "default",
+		Bar: 456,
+	}, q)
+}
+
+func TestUnmarshalQueryParams_ForSliceFields(t *testing.T) {
+	type Query struct {
+		Foo []string  `taqc:"foo"`
+		Bar []int64   `taqc:"bar"`
+		Buz []float64 `taqc:"buz"`
+	}
+
+	var q Query
+	err := UnmarshalQueryParams(url.Values{
+		"foo": []string{"s1", "s2"},
+		"bar": []string{"123", "456"},
+		"buz": []string{"123.456000", "234.567000"},
+	}, &q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, Query{
+		Foo: []string{"s1", "s2"},
+		Bar: []int64{123, 456},
+		Buz: []float64{123.456, 234.567},
+	}, q)
+}
+
+func TestUnmarshalQueryParams_ForPointerFields(t *testing.T) {
+	type Query struct {
+		Foo    *string  `taqc:"foo"`
+		Bar    *int64   `taqc:"bar"`
+		Buz    *float64 `taqc:"buz"`
+		Qux    *bool    `taqc:"qux"`
+		FooBar *bool    `taqc:"foobar"`
+	}
+
+	var q Query
+	err := UnmarshalQueryParams(url.Values{
+		"foo": []string{"str-value"},
+		"bar": []string{"123"},
+		"buz": []string{"456.789000"},
+		"qux": []string{"1"},
+	}, &q)
+	assert.NoError(t, err)
+	assert.Equal(t, "str-value", *q.Foo)
+	assert.Equal(t, int64(123), *q.Bar)
+	assert.Equal(t, 456.789, *q.Buz)
+	assert.True(t, *q.Qux)
+	assert.Nil(t, q.FooBar)
+}
+
+func TestUnmarshalQueryParams_ForNumericFields(t *testing.T) {
+	type Query struct {
+		Int       int       `taqc:"int"`
+		Int8      int8      `taqc:"int8"`
+		Uint16    uint16    `taqc:"uint16"`
+		Uint64    uint64    `taqc:"uint64"`
+		Float32   float32   `taqc:"float32"`
+		Uint32Ptr *uint32   `taqc:"uint32Ptr"`
+		Int32s    []int32   `taqc:"int32s"`
+		Float32s  []float32 `taqc:"float32s"`
+	}
+
+	var q Query
+	err := UnmarshalQueryParams(url.Values{
+		"int":       []string{"-1"},
+		"int8":      []string{"-128"},
+		"uint16":    []string{"65535"},
+		"uint64":    []string{"18446744073709551615"},
+		"float32":   []string{"1.500000"},
+		"uint32Ptr": []string{"456"},
+		"int32s":    []string{"1", "-2"},
+		"float32s":  []string{"0.1"},
+	}, &q)
+	assert.NoError(t, err)
+	u := uint32(456)
+	assert.EqualValues(t, Query{
+		Int:       -1,
+		Int8:      -128,
+		Uint16:    65535,
+		Uint64:    18446744073709551615,
+		Float32:   1.5,
+		Uint32Ptr: &u,
+		Int32s:    []int32{1, -2},
+		Float32s:  []float32{0.1},
+	}, q)
+
+	err = UnmarshalQueryParams(url.Values{"int8": []string{"128"}}, &q)
+	assert.ErrorIs(t, err, ErrInvalidQueryParameterValue)
+
+	err = UnmarshalQueryParams(url.Values{"uint16": []string{"-1"}}, &q)
+	assert.ErrorIs(t, err, ErrInvalidQueryParameterValue)
+}
+
+func TestUnmarshalQueryParams_WithTime(t *testing.T) {
+	type Query struct {
+		UnixSec            time.Time   `taqc:"sec"`
+		UnixMilliSec       time.Time   `taqc:"millisec, unixTimeUnit=millisec"`
+		UnixMicroSec       *time.Time  `taqc:"microsec, unixTimeUnit=microsec"`
+		UnixNanoSec        []time.Time `taqc:"nanosec, unixTimeUnit=nanosec"`
+		RFC3339            time.Time   `taqc:"rfc3339, timeLayout=2006-01-02T15:04:05Z07:00"`
+		PrioritizedRFC3339 time.Time   `taqc:"prioritized_rfc3339, unixTimeUnit=sec, timeLayout=2006-01-02T15:04:05Z07:00"`
+	}
+
+	now := time.Now()
+	var q Query
+	err := UnmarshalQueryParams(url.Values{
+		"sec":                 []string{fmt.Sprintf("%d", now.Unix())},
+		"millisec":            []string{fmt.Sprintf("%d", now.UnixMilli())},
+		"microsec":            []string{fmt.Sprintf("%d", now.UnixMicro())},
+		"nanosec":             []string{fmt.Sprintf("%d", now.UnixNano()), fmt.Sprintf("%d", now.UnixNano())},
+		"rfc3339":             []string{now.Format(time.RFC3339)},
+		"prioritized_rfc3339": []string{now.Format(time.RFC3339)},
+	}, &q)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Unix(), q.UnixSec.Unix())
+	assert.Equal(t, now.UnixMilli(), q.UnixMilliSec.UnixMilli())
+	assert.Equal(t, now.UnixMicro(), q.UnixMicroSec.UnixMicro())
+	assert.Len(t, q.UnixNanoSec, 2)
+	assert.Equal(t, now.UnixNano(), q.UnixNanoSec[0].UnixNano())
+	assert.Equal(t, now.Unix(), q.RFC3339.Unix())
+	assert.Equal(t, now.Unix(), q.PrioritizedRFC3339.Unix())
+}
+
+func TestUnmarshalQueryParams_WithQuotedTimeLayout(t *testing.T) {
+	type Query struct {
+		RFC1123 time.Time `taqc:"rfc1123, timeLayout='Mon, 02 Jan 2006 15:04:05 MST'"`
+	}
+
+	var q Query
+	err := UnmarshalQueryParams(url.Values{
+		"rfc1123": []string{"Mon, 02 Jan 2006 15:04:05 UTC"},
+	}, &q)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), q.RFC1123)
+}
+
+func TestUnmarshalQueryParams_WithKeepEmpty(t *testing.T) {
+	type Query struct {
+		Foo []string `taqc:"foo, keepEmpty"`
+		Bar []string `taqc:"bar"`
+		Buz []int64  `taqc:"buz, keepEmpty"`
+	}
+
+	var q Query
+	err := UnmarshalQueryParams(url.Values{
+		"foo": []string{""},
+		"bar": []string{""},
+	}, &q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, Query{
+		Foo: []string{},
+		Bar: []string{""},
+		Buz: nil,
+	}, q)
+}
+
+func TestUnmarshalQueryParams_RoundTrip(t *testing.T) {
+	type Query struct {
+		Foo   string      `taqc:"foo"`
+		Bar   *int64      `taqc:"bar"`
+		Buz   []float64   `taqc:"buz"`
+		Qux   bool        `taqc:"qux"`
+		Times []time.Time `taqc:"times, unixTimeUnit=millisec"`
+	}
+
+	bar := int64(123)
+	now := time.UnixMilli(time.Now().UnixMilli())
+	original := &Query{
+		Foo:   "str-value",
+		Bar:   &bar,
+		Buz:   []float64{123.456, 234.567},
+		Qux:   true,
+		Times: []time.Time{now, now},
+	}
+
+	qp, err := ConvertToQueryParams(original)
+	assert.NoError(t, err)
+
+	var q Query
+	err = UnmarshalQueryParams(qp, &q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, original, &q)
+}
+
+func TestUnmarshalQueryParams_ShouldRaiseErrorWhenNilValueGiven(t *testing.T) {
+	err := UnmarshalQueryParams(url.Values{}, nil)
+	assert.ErrorIs(t, err, ErrNilValueGiven)
+}
+
+func TestUnmarshalQueryParams_ShouldRaiseErrorWhenNonPointerValueGiven(t *testing.T) {
+	type Query struct {
+		Foo string `taqc:"foo"`
+	}
+
+	err := UnmarshalQueryParams(url.Values{}, Query{})
+	assert.ErrorIs(t, err, ErrNonPointerValueGiven)
+
+	var q *Query
+	err = UnmarshalQueryParams(url.Values{}, q)
+	assert.ErrorIs(t, err, ErrNonPointerValueGiven)
+}
+
+func TestUnmarshalQueryParams_ShouldRaiseErrorWhenTagParamValueIsEmpty(t *testing.T) {
+	type Query struct {
+		Foo string `taqc:""`
+	}
+
+	err := UnmarshalQueryParams(url.Values{}, &Query{})
+	assert.ErrorIs(t, err, ErrQueryParameterNameIsEmpty)
+}
+
+func TestUnmarshalQueryParams_ShouldRaiseErrorWhenInvalidValue(t *testing.T) {
+	type Query struct {
+		Foo int64     `taqc:"foo"`
+		Bar float64   `taqc:"bar"`
+		Buz time.Time `taqc:"buz, timeLayout=2006-01-02"`
+	}
+
+	err := UnmarshalQueryParams(url.Values{"foo": []string{"not-a-number"}}, &Query{})
+	assert.ErrorIs(t, err, ErrInvalidQueryParameterValue)
+
+	err = UnmarshalQueryParams(url.Values{"bar": []string{"not-a-number"}}, &Query{})
+	assert.ErrorIs(t, err, ErrInvalidQueryParameterValue)
+
+	err = UnmarshalQueryParams(url.Values{"buz": []string{"not-a-date"}}, &Query{})
+	assert.ErrorIs(t, err, ErrInvalidQueryParameterValue)
+}
+
+func TestUnmarshalQueryParams_ShouldRaiseErrorWithUnknownTagOption(t *testing.T) {
+	type Query struct {
+		Foo string `taqc:"foo, unknown"`
+	}
+
+	err := UnmarshalQueryParams(url.Values{}, &Query{})
+	assert.ErrorIs(t, err, ErrUnknownTagOption)
+}
+
+func TestUnmarshalQueryParams_ShouldRaiseErrorWithInvalidUnixTimeUnit(t *testing.T) {
+	type Query struct {
+		InvalidUnixSec time.Time `taqc:"invalidUnixSec, unixTimeUnit=INVALID"`
+	}
+
+	err := UnmarshalQueryParams(url.Values{"invalidUnixSec": []string{"123"}}, &Query{})
+	assert.ErrorIs(t, err, ErrUnsupportedUnixTimeUnit)
+}
+
+func TestUnmarshalQueryParams_WithUnsupportedFieldType(t *testing.T) {
+	type Query1 struct {
+		Foo unsupportedStruct `taqc:"foo"`
+	}
+	err := UnmarshalQueryParams(url.Values{"foo": []string{"foo"}}, &Query1{})
+	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+
+	type Query2 struct {
+		Foo *unsupportedStruct `taqc:"foo"`
+	}
+	err = UnmarshalQueryParams(url.Values{"foo": []string{"foo"}}, &Query2{})
+	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+
+	type Query3 struct {
+		Foo []complex128 `taqc:"foo"`
+	}
+	err = UnmarshalQueryParams(url.Values{"foo": []string{"1"}}, &Query3{})
+	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+}
+
+func TestUnmarshalQueryParams_WithNestedStruct(t *testing.T) {
+	type Filter struct {
+		Status string `taqc:"status"`
+		Owner  string `taqc:"owner"`
+	}
+	type Page struct {
+		Size   int64 `taqc:"size"`
+		Number int64 `taqc:"number"`
+	}
+	type Query struct {
+		Filter    *Filter `taqc:"filter, inline, prefix=filter."`
+		Page      Page    `taqc:"page, inline, nestStyle=bracket"`
+		NilFilter *Filter `taqc:"nilFilter, inline, nestStyle=dot"`
+	}
+
+	q := &Query{}
+	err := UnmarshalQueryParams(url.Values{
+		"filter.status": []string{"open"},
+		"page[size]":    []string{"20"},
+		"page[number]":  []string{"2"},
+	}, q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Query{
+		Filter: &Filter{
+			Status: "open",
+		},
+		Page: Page{
+			Size:   20,
+			Number: 2,
+		},
+		NilFilter: nil,
+	}, q)
+
+	err = UnmarshalQueryParams(url.Values{"page[size]": []string{"foo"}}, q)
+	assert.ErrorIs(t, err, ErrInvalidQueryParameterValue)
+	assert.Contains(t, err.Error(), "page[size]")
+}
+
+func TestUnmarshalQueryParams_WithSliceOfStruct(t *testing.T) {
+	type Part struct {
+		Name string `taqc:"name"`
+	}
+	type Item struct {
+		SKU   string            `taqc:"sku"`
+		Qty   *int64            `taqc:"qty"`
+		Attrs map[string]string `taqc:"attrs"`
+		Parts []Part            `taqc:"parts, inline, nestStyle=bracket"`
+	}
+	type Query struct {
+		Items    []Item  `taqc:"items, inline"`
+		PtrItems []*Item `taqc:"ptrItems, inline, indexStyle=dot"`
+		Absent   []Item  `taqc:"absent, inline"`
+	}
+
+	q := &Query{}
+	err := UnmarshalQueryParams(url.Values{
+		"items[0].sku":            []string{"A"},
+		"items[0].qty":            []string{"2"},
+		"items[0].attrs[color]":   []string{"red"},
+		"items[1].parts[0][name]": []string{"p0"},
+		"items[1].parts[1][name]": []string{"p1"},
+		"items[3].sku":            []string{"D"},
+		"ptrItems.0.sku":          []string{"C"},
+	}, q)
+	assert.NoError(t, err)
+	qty := int64(2)
+	assert.EqualValues(t, &Query{
+		Items: []Item{
+			{SKU: "A", Qty: &qty, Attrs: map[string]string{"color": "red"}},
+			{Parts: []Part{{Name: "p0"}, {Name: "p1"}}},
+		},
+		PtrItems: []*Item{
+			{SKU: "C"},
+		},
+	}, q)
+
+	err = UnmarshalQueryParams(url.Values{"items[0].qty": []string{"foo"}}, q)
+	assert.ErrorIs(t, err, ErrInvalidQueryParameterValue)
+	assert.Contains(t, err.Error(), "items[0].qty")
+}
+
+func TestUnmarshalQueryParams_ShouldRaiseErrorWithCyclicNestedStruct(t *testing.T) {
+	type Node struct {
+		Name  string `taqc:"name"`
+		Child *Node  `taqc:"child, inline, nestStyle=dot"`
+	}
+	err := UnmarshalQueryParams(url.Values{"name": []string{"foo"}}, &Node{})
+	assert.ErrorIs(t, err, ErrCyclicNestedStruct)
+}
+
+func TestUnmarshalQueryParams_WithEmbeddedStruct(t *testing.T) {
+	type Query struct {
+		Pagination
+		*Sorting
+		pagination
+		Order string `taqc:"order"`
+	}
+
+	q := &Query{}
+	err := UnmarshalQueryParams(url.Values{
+		"limit": []string{"10"},
+		"page":  []string{"3"},
+		"order": []string{"desc"},
+	}, q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Query{
+		Pagination: Pagination{
+			Limit: 10,
+		},
+		pagination: pagination{
+			Page: 3,
+		},
+		Order: "desc",
+	}, q)
+
+	q = &Query{}
+	err = UnmarshalQueryParams(url.Values{
+		"sort":  []string{"name"},
+		"order": []string{"desc"},
+	}, q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Sorting{Sort: "name"}, q.Sorting)
+	assert.Equal(t, "desc", q.Order)
+}
+
+func TestUnmarshalQueryParams_ShouldRaiseErrorWithUnsettableEmbeddedPointer(t *testing.T) {
+	type Query struct {
+		*pagination
+	}
+	err := UnmarshalQueryParams(url.Values{"page": []string{"1"}}, &Query{})
+	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+}
+
+func TestUnmarshalQueryParams_WithMap(t *testing.T) {
+	type Key string
+	type Query struct {
+		Filter map[string]string    `taqc:"filter"`
+		Tags   map[string][]string  `taqc:"tags"`
+		Counts map[Key]int64        `taqc:"counts, prefix=c_"`
+		Times  map[string]time.Time `taqc:"times, unixTimeUnit=millisec"`
+		Nil    map[string]string    `taqc:"nil"`
+		Q      string               `taqc:"q"`
+		Rest   map[string]string    `taqc:"rest, mapStyle=flat"`
+	}
+
+	q := &Query{}
+	err := UnmarshalQueryParams(url.Values{
+		"filter[status]": []string{"open", "closed"},
+		"filter[owner]":  []string{"me"},
+		"tags[label]":    []string{"bug", "help"},
+		"c_a":            []string{"1"},
+		"times[since]":   []string{"1640000000123"},
+		"q":              []string{"foo"},
+		"other":          []string{"bar"},
+	}, q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, map[string]string{"status": "open", "owner": "me"}, q.Filter)
+	assert.EqualValues(t, map[string][]string{"label": {"bug", "help"}}, q.Tags)
+	assert.EqualValues(t, map[Key]int64{"a": 1}, q.Counts)
+	assert.True(t, time.UnixMilli(1640000000123).Equal(q.Times["since"]))
+	assert.Nil(t, q.Nil)
+	assert.Equal(t, "foo", q.Q)
+	assert.Equal(t, "bar", q.Rest["other"])
+	assert.NotContains(t, q.Rest, "q")
+
+	err = UnmarshalQueryParams(url.Values{"c_a": []string{"foo"}}, q)
+	assert.ErrorIs(t, err, ErrInvalidQueryParameterValue)
+}
+
+func TestUnmarshalQueryParams_WithFlatMapNextToCollections(t *testing.T) {
+	type Item struct {
+		SKU  string            `taqc:"sku"`
+		Rest map[string]string `taqc:"rest, mapStyle=flat"`
+		Tags []string          `taqc:"tags, collectionFormat=brackets"`
+	}
+	type Query struct {
+		IDs      []int64           `taqc:"ids, collectionFormat=brackets"`
+		Indexed  []string          `taqc:"idx, collectionFormat=indexed"`
+		Items    []Item            `taqc:"items, inline"`
+		PtrItems []*Item           `taqc:"ptrItems, inline, indexStyle=dot, nestStyle=bracket"`
+		Rest     map[string]string `taqc:"rest, mapStyle=flat"`
+	}
+
+	q := &Query{}
+	err := UnmarshalQueryParams(url.Values{
+		"ids[]":             []string{"1", "2"},
+		"idx[0]":            []string{"a"},
+		"idx[1]":            []string{"b"},
+		"items[0].sku":      []string{"A"},
+		"items[0].tags[]":   []string{"x"},
+		"items[0].other":    []string{"in item"},
+		"ptrItems.0[sku]":   []string{"B"},
+		"ptrItems.0[other]": []string{"in ptr item"},
+		"other":             []string{"top"},
+		"idx[x]":            []string{"not indexed"},
+	}, q)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, q.IDs)
+	assert.Equal(t, []string{"a", "b"}, q.Indexed)
+	// the map takes only the parameters that no other field produces
+	assert.EqualValues(t, map[string]string{"other": "top", "idx[x]": "not indexed"}, q.Rest)
+	assert.Equal(t, []Item{{SKU: "A", Tags: []string{"x"}, Rest: map[string]string{"other": "in item"}}}, q.Items)
+	assert.Equal(t, []*Item{{SKU: "B", Rest: map[string]string{"other": "in ptr item"}}}, q.PtrItems)
+}
+
+func TestUnmarshalQueryParams_ShouldCacheDecodingOptions(t *testing.T) {
+	type Query struct {
+		IDs  []int64          `taqc:"ids, collectionFormat=brackets, keepEmpty"`
+		Flag bool             `taqc:"flag, boolFormat=yesno"`
+		Rest map[string]int64 `taqc:"rest, mapStyle=flat"`
+	}
+
+	var q Query
+	err := UnmarshalQueryParams(url.Values{"ids[]": []string{"1"}, "flag": []string{"yes"}, "n": []string{"2"}}, &q)
+	assert.NoError(t, err)
+	assert.Equal(t, Query{IDs: []int64{1}, Flag: true, Rest: map[string]int64{"n": 2}}, q)
+
+	fields, ok := structFieldsCache.Load(reflect.TypeOf(Query{}))
+	assert.True(t, ok)
+	ids, flag, rest := fields.([]*structField)[0], fields.([]*structField)[1], fields.([]*structField)[2]
+	assert.Equal(t, internal.CollectionFormatBrackets, ids.collectionFormat)
+	assert.True(t, ids.keepEmpty)
+	assert.Equal(t, internal.BoolFormatYesNo, flag.parsers.boolFormat)
+	assert.NotNil(t, rest.parsers)
+	assert.Contains(t, rest.reservedPatterns, internal.ParamPattern{Prefix: "ids[]"})
+}
+
+func TestUnmarshalQueryParams_WithCollectionFormat(t *testing.T) {
+	type Query struct {
+		CSV     []int64  `taqc:"csv, collectionFormat=csv, keepEmpty"`
+		Indexed []string `taqc:"indexed, collectionFormat=indexed"`
+	}
+
+	q := &Query{}
+	err := UnmarshalQueryParams(url.Values{
+		"csv":        []string{"1,2,3"},
+		"indexed[0]": []string{"a"},
+		"indexed[1]": []string{"b"},
+		"indexed[3]": []string{"d"},
+	}, q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int64{1, 2, 3}, q.CSV)
+	assert.EqualValues(t, []string{"a", "b"}, q.Indexed)
+
+	q = &Query{}
+	err = UnmarshalQueryParams(url.Values{"csv": []string{""}}, q)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int64{}, q.CSV)
+
+	err = UnmarshalQueryParams(url.Values{"csv": []string{"1,foo"}}, q)
+	assert.ErrorIs(t, err, ErrInvalidQueryParameterValue)
+
+	err = UnmarshalQueryParams(url.Values{"csv": []string{"1,%zz"}}, q)
+	assert.ErrorIs(t, err, ErrInvalidQueryParameterValue)
+}
+
+func TestUnmarshalQueryParams_WithTextUnmarshaler(t *testing.T) {
+	type Query struct {
+		Status    Status   `taqc:"status"`
+		StatusPtr *Status  `taqc:"status_ptr"`
+		Statuses  []Status `taqc:"statuses, collectionFormat=csv"`
+	}
+
+	var q Query
+	err := UnmarshalQueryParams(url.Values{
+		"status":     []string{"active"},
+		"status_ptr": []string{"closed"},
+		"statuses":   []string{"closed,active"},
+	}, &q)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusActive, q.Status)
+	assert.Equal(t, StatusClosed, *q.StatusPtr)
+	assert.Equal(t, []Status{StatusClosed, StatusActive}, q.Statuses)
+
+	err = UnmarshalQueryParams(url.Values{"status": []string{"unknown"}}, &q)
+	assert.ErrorIs(t, err, ErrInvalidQueryParameterValue)
+	assert.Contains(t, err.Error(), errInvalidStatus.Error())
+}
+
+func TestUnmarshalQueryParams_ShouldRaiseErrorWithStringer(t *testing.T) {
+	type Query struct {
+		Color  Color   `taqc:"color"`
+		Colors []Color `taqc:"colors"`
+	}
+
+	// the value that `String()` encodes cannot be decoded without `UnmarshalText()`
+	var q Query
+	err := UnmarshalQueryParams(url.Values{"color": []string{"green"}}, &q)
+	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+	assert.Contains(t, err.Error(), "UnmarshalText()")
+
+	err = UnmarshalQueryParams(url.Values{"colors": []string{"1"}}, &q)
+	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+}
+
+func TestUnmarshalQueryParams_WithDuration(t *testing.T) {
+	type Query struct {
+		Default  time.Duration   `taqc:"default"`
+		Millisec time.Duration   `taqc:"millisec, durationUnit=ms"`
+		Hour     time.Duration   `taqc:"hour, durationUnit=h"`
+		Go       time.Duration   `taqc:"go, durationFormat=go"`
+		Ptr      *time.Duration  `taqc:"ptr, durationUnit=s"`
+		Slice    []time.Duration `taqc:"slice, durationFormat=go, collectionFormat=csv"`
+	}
+
+	var q Query
+	err := UnmarshalQueryParams(url.Values{
+		"default":  []string{"1500"},
+		"millisec": []string{"1500"},
+		"hour":     []string{"2"},
+		"go":       []string{"1h30m30.5s"},
+		"ptr":      []string{"90"},
+		"slice":    []string{"1s,1m30s"},
+	}, &q)
+	assert.NoError(t, err)
+	assert.Equal(t, 1500*time.Nanosecond, q.Default)
+	assert.Equal(t, 1500*time.Millisecond, q.Millisec)
+	assert.Equal(t, 2*time.Hour, q.Hour)
+	assert.Equal(t, 90*time.Minute+30500*time.Millisecond, q.Go)
+	assert.Equal(t, 90*time.Second, *q.Ptr)
+	assert.Equal(t, []time.Duration{time.Second, 90 * time.Second}, q.Slice)
+
+	err = UnmarshalQueryParams(url.Values{"go": []string{"90"}}, &q)
+	assert.ErrorIs(t, err, ErrInvalidQueryParameterValue)
+
+	type InvalidQuery struct {
+		Duration time.Duration `taqc:"duration, durationUnit=d"`
+	}
+	err = UnmarshalQueryParams(url.Values{"duration": []string{"1"}}, &InvalidQuery{})
+	assert.ErrorIs(t, err, ErrUnsupportedDurationUnit)
+}
+
+func TestUnmarshalQueryParams_WithTimeZone(t *testing.T) {
+	type Query struct {
+		Tokyo       time.Time  `taqc:"tokyo, timeLayout=2006-01-02 15:04, timeZone=Asia/Tokyo"`
+		TokyoPtr    *time.Time `taqc:"tokyo_ptr, timeLayout=2006-01-02T15:04:05Z07:00, timeZone=Asia/Tokyo"`
+		UnixInUTC   time.Time  `taqc:"unix_in_utc, timeZone=UTC"`
+		WithoutZone time.Time  `taqc:"without_zone, timeLayout=2006-01-02 15:04"`
+	}
+
+	var q Query
+	err := UnmarshalQueryParams(url.Values{
+		"tokyo":        []string{"2022-01-01 10:30"},
+		"tokyo_ptr":    []string{"2021-12-31T20:30:00-05:00"},
+		"unix_in_utc":  []string{"1641000600"},
+		"without_zone": []string{"2022-01-01 10:30"},
+	}, &q)
+	assert.NoError(t, err)
+
+	expected := time.Date(2022, 1, 1, 1, 30, 0, 0, time.UTC)
+	assert.True(t, expected.Equal(q.Tokyo))
+	assert.Equal(t, "Asia/Tokyo", q.Tokyo.Location().String())
+	assert.True(t, expected.Equal(*q.TokyoPtr))
+	assert.Equal(t, "Asia/Tokyo", q.TokyoPtr.Location().String())
+	assert.True(t, expected.Equal(q.UnixInUTC))
+	assert.Equal(t, time.UTC, q.UnixInUTC.Location())
+	assert.True(t, time.Date(2022, 1, 1, 10, 30, 0, 0, time.UTC).Equal(q.WithoutZone))
+
+	type InvalidQuery struct {
+		Time time.Time `taqc:"time, timeZone=Mars/Olympus_Mons"`
+	}
+	err = UnmarshalQueryParams(url.Values{"time": []string{"1"}}, &InvalidQuery{})
+	assert.ErrorIs(t, err, ErrUnknownTimeZone)
+}
+
+func TestUnmarshalQueryParams_WithBoolFormat(t *testing.T) {
+	type Query struct {
+		Default    bool              `taqc:"default"`
+		Numeric    bool              `taqc:"numeric, boolFormat=numeric"`
+		TrueFalse  bool              `taqc:"true_false, boolFormat=truefalse"`
+		YesNo      *bool             `taqc:"yes_no, boolFormat=yesno"`
+		Presence   bool              `taqc:"presence, boolFormat=presence"`
+		NoPresence bool              `taqc:"no_presence, boolFormat=presence"`
+		Bools      []bool            `taqc:"bools"`
+		CSVBools   []bool            `taqc:"csv_bools, boolFormat=truefalse, collectionFormat=csv"`
+		Map        map[string][]bool `taqc:"map, boolFormat=yesno"`
+	}
+
+	qp, err := url.ParseQuery("default=1&numeric=0&true_false=true&yes_no=no&presence&bools=1&bools=0&csv_bools=false,true&map[a]=yes&map[a]=no")
+	assert.NoError(t, err)
+
+	q := Query{Numeric: true}
+	err = UnmarshalQueryParams(qp, &q)
+	assert.NoError(t, err)
+	assert.Equal(t, Query{
+		Default:   true,
+		Numeric:   false,
+		TrueFalse: true,
+		YesNo:     func() *bool { b := false; return &b }(),
+		Presence:  true,
+		Bools:     []bool{true, false},
+		CSVBools:  []bool{false, true},
+		Map:       map[string][]bool{"a": {true, false}},
+	}, q)
+}
+
+func TestUnmarshalQueryParams_ShouldRaiseErrorWithInvalidBoolValue(t *testing.T) {
+	type Query struct {
+		Foo bool `taqc:"foo, boolFormat=truefalse"`
+	}
+	err := UnmarshalQueryParams(url.Values{"foo": []string{"1"}}, &Query{})
+	assert.ErrorIs(t, err, ErrInvalidQueryParameterValue)
+
+	type SliceQuery struct {
+		Foo []bool `taqc:"foo, boolFormat=presence"`
+	}
+	err = UnmarshalQueryParams(url.Values{"foo": []string{""}}, &SliceQuery{})
+	assert.ErrorIs(t, err, ErrUnsupportedBoolFormat)
+}